@@ -2,65 +2,98 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/stardust1405/stardocker/src"
 
 	tea "github.com/charmbracelet/bubbletea"
-	vanillaDockerClient "github.com/docker/docker/client"
-	"github.com/docker/go-sdk/client"
 )
 
+// stringList collects a flag that may be given more than once.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func main() {
-	ctx := context.TODO()
+	var endpointSpecs stringList
+	flag.Var(&endpointSpecs, "endpoint", "extra daemon to show alongside the current one, as name=host[,tls=certdir] where host is a context name or a unix://, tcp:// or ssh:// URL (repeatable)")
+	configFile := flag.String("config", "", "config file (defaults to "+src.DefaultConfigPath()+")")
+	contextName := flag.String("context", "", "Docker context to connect to (defaults to DOCKER_HOST, DOCKER_CONTEXT or the current context)")
+	launcherName := flag.String("launcher", src.LauncherAuto, "how to start the Docker daemon if it isn't running ("+strings.Join(src.LauncherNames, ", ")+")")
+	startTimeout := flag.Duration("start-timeout", 60*time.Second, "how long to wait for the Docker daemon to become ready")
+	readOnly := flag.Bool("read-only", false, "turn off everything that changes containers or networks")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "usage: stardocker [flags] [command]")
+		fmt.Fprintln(out, "\nWithout a command the UI starts. Commands print and exit:")
+		for _, usage := range src.CLIUsages() {
+			fmt.Fprintln(out, "  "+usage)
+		}
+		fmt.Fprintln(out, "\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-	// Try creating a Docker client
-	cli, err := vanillaDockerClient.NewClientWithOpts()
+	path := *configFile
+	if path == "" {
+		path = src.DefaultConfigPath()
+	} else if _, err := os.Stat(path); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	src.SetupTerminal()
+	config, err := src.LoadConfig(path)
 	if err != nil {
-		panic(err)
+		fmt.Println(err)
+		os.Exit(2)
 	}
+	src.SetConfig(path, config)
+	src.SetStatePath(src.DefaultStatePath())
+	src.SetReadOnly(*readOnly)
 
-	// Check if Docker is alive
-	_, err = cli.Ping(ctx)
+	launcher, err := src.NewDaemonLauncher(*launcherName)
 	if err != nil {
-		fmt.Println("Docker daemon not running. Starting Docker Desktop...")
+		fmt.Println(err)
+		os.Exit(2)
+	}
 
-		// Start Docker Desktop headless
-		exec.Command("open", "-a", "Docker", "--args", "--unattended").Start()
+	// Cancelled on the way out so no Docker call outlives the UI
+	ctx, cancel := context.WithCancel(context.Background())
+	src.SetRootContext(ctx)
 
-		// Wait for daemon to come up
-		for {
-			_, err = cli.Ping(ctx)
-			if err == nil {
-				fmt.Println("Docker is ready!")
-				break
-			}
-			fmt.Println("Waiting for Docker daemon...")
-			time.Sleep(2 * time.Second)
+	endpoints := []src.Endpoint{}
+	for _, spec := range endpointSpecs {
+		endpoint, err := src.ParseEndpoint(spec)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
 		}
-	} else {
-		fmt.Println("Docker already running.")
+		endpoints = append(endpoints, endpoint)
 	}
 
-	// Close the client when done
-	err = cli.Close()
-	if err != nil {
-		panic(err)
+	if flag.NArg() > 0 {
+		src.ConnectEndpoints(endpoints)
+		code := src.RunCLI(*contextName, flag.Args(), os.Stdout, os.Stderr)
+		cancel()
+		os.Exit(code)
 	}
 
-	dockerClient, err := client.New(ctx)
+	src.SetEndpoints(endpoints)
+	p := tea.NewProgram(src.InitRouterModel(src.InitStartupModel(*contextName, launcher, *startTimeout)), tea.WithAltScreen())
+	_, err = p.Run()
+	cancel()
 	if err != nil {
-		panic(err)
-	}
-
-	// Close the docker client when done
-	defer dockerClient.Close()
-
-	p := tea.NewProgram(src.InitIndexModel(dockerClient), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error encountered, terminating: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,244 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// Containers marked in the containers table are started, stopped,
+// restarted or removed together: the calls all go out at once and a
+// summary lists how each of them went.
+
+// bulkAction is something that can be done to many containers at once.
+type bulkAction struct {
+	name string
+	// done is the past tense, for reporting
+	done string
+	// destructive actions are confirmed first
+	destructive bool
+	call        func(ctx context.Context, dockerClient Docker, containerID string) error
+}
+
+var (
+	startAction   = bulkAction{name: "start", done: "Started", call: StartContainer}
+	stopAction    = bulkAction{name: "stop", done: "Stopped", destructive: true, call: StopContainer}
+	restartAction = bulkAction{name: "restart", done: "Restarted", destructive: true, call: RestartContainer}
+	removeAction  = bulkAction{name: "remove", done: "Removed", destructive: true, call: RemoveContainer}
+)
+
+// maxConfirmDetails is how many containers a confirmation lists by name.
+const maxConfirmDetails = 10
+
+// confirmContainers asks before run applies action to containers. The
+// names of protected containers have to be typed in.
+func confirmContainers(action bulkAction, containers []Container, run tea.Cmd) tea.Cmd {
+	verb := strings.ToUpper(action.name[:1]) + action.name[1:]
+	question := fmt.Sprintf("%s %s?", verb, containers[0].Name)
+	if len(containers) > 1 {
+		question = fmt.Sprintf("%s %d containers?", verb, len(containers))
+	}
+
+	details, typed := []string{}, []string{}
+	for i, c := range containers {
+		name := stackKey(c.Host, c.Name)
+		if protected(c) {
+			typed = append(typed, c.Name)
+			name += " (protected)"
+		}
+		if i < maxConfirmDetails {
+			details = append(details, "• "+name)
+		}
+	}
+	if len(containers) > maxConfirmDetails {
+		details = append(details, fmt.Sprintf("  and %d more", len(containers)-maxConfirmDetails))
+	}
+	if len(containers) == 1 && len(typed) == 0 {
+		details = nil
+	}
+
+	return confirm(confirmMsg{question: question, details: details, typed: strings.Join(typed, " "), yes: run})
+}
+
+// startOrStop stops the containers that are running, or starts the exited
+// ones when none is. Like on a single row, containers in other states,
+// created or paused, are left alone.
+func startOrStop(containers []Container) (bulkAction, []Container) {
+	running, exited := []Container{}, []Container{}
+	for _, c := range containers {
+		switch c.State {
+		case containerTypes.StateRunning:
+			running = append(running, c)
+		case containerTypes.StateExited:
+			exited = append(exited, c)
+		}
+	}
+	if len(running) > 0 {
+		return stopAction, running
+	}
+	return startAction, exited
+}
+
+// bulkResult is how an action went for one container.
+type bulkResult struct {
+	Container
+	err error
+}
+
+type bulkMsg struct {
+	viewID  int64
+	action  bulkAction
+	results []bulkResult
+}
+
+// bulkCmd applies action to every container at the same time, each through
+// the client of its host.
+func bulkCmd(viewID int64, dockerClient Docker, action bulkAction, containers []Container) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, len(containers))
+		var wg sync.WaitGroup
+		for i, c := range containers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := callCtx()
+				defer cancel()
+
+				client, err := hostClient(dockerClient, c.Host)
+				if err == nil {
+					err = action.call(ctx, client, c.ID)
+				}
+				results[i] = bulkResult{Container: c, err: err}
+			}()
+		}
+		wg.Wait()
+		return bulkMsg{viewID: viewID, action: action, results: results}
+	}
+}
+
+// Bulk Summary Model
+
+type summaryKeys struct {
+	globalKeys
+	Close key.Binding
+}
+
+func defaultSummaryKeys() summaryKeys {
+	return summaryKeys{
+		globalKeys: defaultGlobalKeys(),
+		Close: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "close"),
+		),
+	}
+}
+
+func newSummaryKeys() summaryKeys {
+	k := defaultSummaryKeys()
+	remap("global", &k.globalKeys)
+	remap("summary", &k)
+	return k
+}
+
+func (k *summaryKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"close": &k.Close,
+	}
+}
+
+func (k summaryKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Close, k.Back, k.Help}
+}
+
+func (k summaryKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Close}}, k.globalKeys.FullHelp()...)
+}
+
+// bulkSummaryModel shows how a bulk action went, container by container.
+type bulkSummaryModel struct {
+	keys   summaryKeys
+	msg    bulkMsg
+	width  int
+	height int
+}
+
+func InitBulkSummaryModel(msg bulkMsg, width int, height int) bulkSummaryModel {
+	return bulkSummaryModel{
+		keys:   newSummaryKeys(),
+		msg:    msg,
+		width:  width,
+		height: height,
+	}
+}
+
+func (b bulkSummaryModel) Init() tea.Cmd {
+	return nil
+}
+
+func (b bulkSummaryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		b.keys = newSummaryKeys()
+		return b, nil
+
+	case tea.WindowSizeMsg:
+		b.width = msg.Width
+		b.height = msg.Height
+		return b, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, b.keys.Help):
+			return b, showHelp("summary", b.keys)
+		case key.Matches(msg, b.keys.Close), key.Matches(msg, b.keys.Back):
+			return b, pop()
+		case key.Matches(msg, b.keys.Quit):
+			return b, tea.Quit
+		}
+	}
+
+	return b, nil
+}
+
+func (b bulkSummaryModel) View() string {
+	doc := strings.Builder{}
+
+	succeeded := 0
+	for _, r := range b.msg.results {
+		if r.err == nil {
+			succeeded++
+		}
+	}
+	heading := fmt.Sprintf("%s · %d of %d done", strings.ToUpper(b.msg.action.name), succeeded, len(b.msg.results))
+	doc.WriteString(lipgloss.PlaceHorizontal(b.width, lipgloss.Left, ContainerTitleStyle.Render(heading)))
+	doc.WriteString("\n\n")
+
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Success))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Error))
+	width := 0
+	for _, r := range b.msg.results {
+		width = max(width, len(stackKey(r.Host, r.Name)))
+	}
+	lines := []string{}
+	for _, r := range b.msg.results {
+		name := fmt.Sprintf("%-*s", width, stackKey(r.Host, r.Name))
+		if r.err != nil {
+			lines = append(lines, failStyle.Render("✖ "+name)+"  "+r.err.Error())
+			continue
+		}
+		lines = append(lines, okStyle.Render("✔ "+name)+"  "+b.msg.action.done)
+	}
+	dialog := DialogStyle.MaxWidth(max(0, b.width-1)).Render(strings.Join(lines, "\n"))
+	doc.WriteString(dialog + "\n\n")
+
+	doc.WriteString(helpLine(b.keys))
+
+	return doc.String()
+}
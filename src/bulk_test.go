@@ -0,0 +1,41 @@
+package src
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestStartOrStop(t *testing.T) {
+	running := Container{ID: "6f1c2b7d9e0a4c3b8a7d", State: container.StateRunning}
+	exited := Container{ID: "0b9e8d7c6a5f4e3d2c1b", State: container.StateExited}
+	created := Container{ID: "5d4c3b2a1f0e9d8c7b6a", State: container.StateCreated}
+	paused := Container{ID: "9a8b7c6d5e4f30211203", State: container.StatePaused}
+
+	tests := []struct {
+		name       string
+		containers []Container
+		action     bulkAction
+		want       []Container
+	}{
+		{"some running", []Container{exited, running, created}, stopAction, []Container{running}},
+		{"none running", []Container{exited, created, paused}, startAction, []Container{exited}},
+		{"nothing to start", []Container{created, paused}, startAction, []Container{}},
+	}
+
+	ids := func(containers []Container) []string {
+		ids := []string{}
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	for _, tt := range tests {
+		action, containers := startOrStop(tt.containers)
+		if action.name != tt.action.name || !slices.Equal(ids(containers), ids(tt.want)) {
+			t.Errorf("%s: startOrStop = %s %v, want %s %v", tt.name, action.name, ids(containers), tt.action.name, ids(tt.want))
+		}
+	}
+}
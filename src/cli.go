@@ -0,0 +1,396 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// The subcommands print what the UI shows and exit, for scripts and CI:
+//
+//	stardocker ps [--json | --format TEMPLATE]
+//	stardocker logs [HOST/]NAME [--stack]
+//	stardocker stacks [--json]
+//
+// They go through the same calls as the screens, so compose projects are
+// grouped the same way and the daemons given with --endpoint are covered
+// too: ps and stacks list every daemon, logs reads HOST/NAME from the
+// endpoint called HOST.
+
+type cliCommand struct {
+	usage string
+	run   func(ctx context.Context, dockerClient Docker, args []string, out io.Writer) error
+}
+
+var cliCommands = map[string]cliCommand{
+	"ps":     {usage: "ps [--json | --format TEMPLATE]", run: runPs},
+	"logs":   {usage: "logs [HOST/]NAME [--stack]", run: runLogs},
+	"stacks": {usage: "stacks [--json]", run: runStacks},
+}
+
+// usageError is a mistake on the command line, which exits with 2.
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// CLIUsages lists the subcommands for the usage message.
+func CLIUsages() []string {
+	return []string{cliCommands["ps"].usage, cliCommands["logs"].usage, cliCommands["stacks"].usage}
+}
+
+// RunCLI runs the subcommand in args against the daemon of contextName and
+// returns the exit code.
+func RunCLI(contextName string, args []string, stdout io.Writer, stderr io.Writer) int {
+	if _, ok := cliCommands[args[0]]; !ok {
+		fmt.Fprintf(stderr, "unknown command %q, use one of: %s\n", args[0], strings.Join(slices.Sorted(maps.Keys(cliCommands)), ", "))
+		return 2
+	}
+
+	ctx, cancel := callCtx()
+	dockerClient, err := NewDockerClient(ctx, contextName)
+	cancel()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer dockerClient.Close()
+
+	if err := runCLI(rootCtx, dockerClient, args, stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		if errors.As(err, &usageError{}) {
+			fmt.Fprintln(stderr, "usage: stardocker "+cliCommands[args[0]].usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// runCLI runs a subcommand against dockerClient.
+func runCLI(ctx context.Context, dockerClient Docker, args []string, out io.Writer) error {
+	c, ok := cliCommands[args[0]]
+	if !ok {
+		return usageErrorf("unknown command %q", args[0])
+	}
+	return c.run(ctx, dockerClient, args[1:], out)
+}
+
+// newFlagSet makes a flag set that reports errors instead of exiting.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%s", err)
+	}
+	return nil
+}
+
+// CLIContainer is a container or compose stack as ps prints it, in JSON or
+// to --format templates.
+type CLIContainer struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Type   ContainerType `json:"type"`
+	Image  string        `json:"image,omitempty"`
+	State  string        `json:"state,omitempty"`
+	Status string        `json:"status,omitempty"`
+	Ports  []string      `json:"ports,omitempty"`
+	Host   string        `json:"host,omitempty"`
+	// Stack is the compose project of a service
+	Stack    string         `json:"stack,omitempty"`
+	Children []CLIContainer `json:"children,omitempty"`
+}
+
+func toCLIContainer(c Container, stack string) CLIContainer {
+	out := CLIContainer{
+		ID:     c.ID,
+		Name:   c.Name,
+		Type:   c.Type,
+		Image:  c.Image,
+		State:  c.State,
+		Status: c.Status,
+		Host:   c.Host,
+		Stack:  stack,
+	}
+	for _, p := range dedupePorts(c.Ports) {
+		out.Ports = append(out.Ports, formatPort(p, "->"))
+	}
+	for _, child := range c.Children {
+		out.Children = append(out.Children, toCLIContainer(child, c.Name))
+	}
+	return out
+}
+
+func runPs(ctx context.Context, dockerClient Docker, args []string, out io.Writer) error {
+	flags := newFlagSet("ps")
+	asJSON := flags.Bool("json", false, "")
+	format := flags.String("format", "", "")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *asJSON && *format != "" {
+		return usageErrorf("--json and --format don't go together")
+	}
+
+	containers, err := FetchAllContainers(ctx, dockerClient)
+	if err != nil {
+		return err
+	}
+	entries := []CLIContainer{}
+	// The containers of the endpoints that answered are printed all the same
+	unreachable := []error{}
+	for _, c := range containers {
+		if c.Type == TypeUnreachableHost {
+			unreachable = append(unreachable, fmt.Errorf("%s: %s", c.Host, c.Status))
+			continue
+		}
+		entries = append(entries, toCLIContainer(c, ""))
+	}
+
+	switch {
+	case *asJSON:
+		err = writeJSON(out, entries)
+	case *format != "":
+		err = writeFormat(out, *format, entries)
+	default:
+		err = writePsTable(out, entries, len(RemoteHosts()) > 0)
+	}
+	if err != nil {
+		return err
+	}
+	return errors.Join(unreachable...)
+}
+
+// writePsTable prints entries the way docker ps does, with the host of
+// every container when there are endpoints.
+func writePsTable(out io.Writer, entries []CLIContainer, withHost bool) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := "NAME\tID\tIMAGE\tSTATE\tSTATUS\tPORTS"
+	if withHost {
+		header = "HOST\t" + header
+	}
+	fmt.Fprintln(w, header)
+	for _, c := range flatten(entries) {
+		name := c.Name
+		if c.Stack != "" {
+			name = "  " + name
+		}
+		// A stack's ID is made up, nothing docker takes
+		id := shortID(c.ID)
+		if c.Type == TypeComposeStack {
+			id = ""
+		}
+		row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", name, id, c.Image, c.State, c.Status, strings.Join(c.Ports, ", "))
+		if withHost {
+			row = c.Host + "\t" + row
+		}
+		fmt.Fprintln(w, row)
+	}
+	return w.Flush()
+}
+
+// flatten lists stacks followed by their services.
+func flatten(entries []CLIContainer) []CLIContainer {
+	flat := []CLIContainer{}
+	for _, c := range entries {
+		flat = append(flat, c)
+		flat = append(flat, c.Children...)
+	}
+	return flat
+}
+
+func writeJSON(out io.Writer, v any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeFormat prints every container and stack service through the Go
+// template format, one per line.
+func writeFormat(out io.Writer, format string, entries []CLIContainer) error {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return usageErrorf("--format: %s", err)
+	}
+	for _, c := range flatten(entries) {
+		if err := tmpl.Execute(out, c); err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
+
+func runLogs(ctx context.Context, dockerClient Docker, args []string, out io.Writer) error {
+	flags := newFlagSet("logs")
+	stack := flags.Bool("stack", false, "")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("logs takes a container or stack name")
+	}
+	// Flags may come after the name too
+	name := flags.Arg(0)
+	if err := parseFlags(flags, flags.Args()[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("logs takes one container or stack name")
+	}
+	dockerClient, name, err := cliClient(dockerClient, name)
+	if err != nil {
+		return err
+	}
+
+	if !*stack {
+		c, err := FindContainer(ctx, dockerClient, name)
+		if err != nil {
+			return err
+		}
+		logs, err := GetContainerLogs(ctx, dockerClient, c.ID, false)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(out, logs)
+		return err
+	}
+
+	services, err := findStack(ctx, dockerClient, name)
+	if err != nil {
+		return err
+	}
+	width := 0
+	for _, s := range services {
+		width = max(width, len(s.Name))
+	}
+	for _, s := range services {
+		logs, err := GetContainerLogs(ctx, dockerClient, s.ID, false)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+		for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			fmt.Fprintf(out, "%-*s | %s\n", width, s.Name, line)
+		}
+	}
+	return nil
+}
+
+// cliClient picks the daemon a name given on the command line is on:
+// HOST/NAME is NAME on the endpoint called HOST, anything else is on the
+// local daemon. Container and compose project names can't hold a slash.
+func cliClient(dockerClient Docker, name string) (Docker, string, error) {
+	if host, rest, ok := strings.Cut(name, "/"); ok && RemoteHost(host) != nil {
+		client, err := hostClient(dockerClient, host)
+		return client, rest, err
+	}
+	return dockerClient, name, nil
+}
+
+// findStack returns the services of the compose project called name, or of
+// the project the container called name belongs to.
+func findStack(ctx context.Context, dockerClient Docker, name string) ([]Container, error) {
+	containers, err := FetchContainers(ctx, dockerClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		if c.Type != TypeComposeStack {
+			continue
+		}
+		if c.Name == name {
+			return c.Children, nil
+		}
+		for _, child := range c.Children {
+			if child.Name == name {
+				return c.Children, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no compose stack named %q or holding a container of that name", name)
+}
+
+// CLIStack is a compose project as stacks prints it.
+type CLIStack struct {
+	Name     string   `json:"name"`
+	Host     string   `json:"host,omitempty"`
+	Services []string `json:"services"`
+	Running  int      `json:"running"`
+}
+
+func runStacks(ctx context.Context, dockerClient Docker, args []string, out io.Writer) error {
+	flags := newFlagSet("stacks")
+	asJSON := flags.Bool("json", false, "")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("stacks takes no arguments")
+	}
+
+	containers, err := FetchAllContainers(ctx, dockerClient)
+	if err != nil {
+		return err
+	}
+	stacks := []CLIStack{}
+	// The stacks of the endpoints that answered are printed all the same
+	unreachable := []error{}
+	for _, c := range containers {
+		if c.Type == TypeUnreachableHost {
+			unreachable = append(unreachable, fmt.Errorf("%s: %s", c.Host, c.Status))
+		}
+		if c.Type != TypeComposeStack {
+			continue
+		}
+		s := CLIStack{Name: c.Name, Host: c.Host, Services: []string{}}
+		for _, child := range c.Children {
+			s.Services = append(s.Services, child.Name)
+			if child.State == containerTypes.StateRunning {
+				s.Running++
+			}
+		}
+		stacks = append(stacks, s)
+	}
+
+	if *asJSON {
+		if err := writeJSON(out, stacks); err != nil {
+			return err
+		}
+		return errors.Join(unreachable...)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tRUNNING\tSERVICES")
+	for _, s := range stacks {
+		fmt.Fprintf(w, "%s\t%d/%d\t%s\n", stackKey(s.Host, s.Name), s.Running, len(s.Services), strings.Join(s.Services, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return errors.Join(unreachable...)
+}
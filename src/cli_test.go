@@ -0,0 +1,153 @@
+package src
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestCLI(t *testing.T) {
+	f := testDocker()
+	f.setLogs("a1b2c3d4e5f60718293a", "listening on :8080\n")
+	f.setLogs("f0e1d2c3b4a596877869", "database system is ready\ncheckpoint complete\n")
+	f.setTTY("0b9e8d7c6a5f4e3d2c1b")
+	f.setLogs("0b9e8d7c6a5f4e3d2c1b", "\x1b[31mjob failed\x1b[0m\r\n")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"ps", []string{"ps"}},
+		{"ps format", []string{"ps", "--format", "{{.Name}} {{.Stack}}"}},
+		{"logs", []string{"logs", "nginx"}},
+		{"logs stack", []string{"logs", "shop-db", "--stack"}},
+		{"stacks", []string{"stacks"}},
+		{"stacks json", []string{"stacks", "--json"}},
+		{"logs tty", []string{"logs", "worker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := strings.Builder{}
+			if err := runCLI(t.Context(), f, tt.args, &out); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, out.String())
+		})
+	}
+}
+
+// useRemoteHost registers dockerClient as the endpoint e for the rest of
+// the test.
+func useRemoteHost(t *testing.T, e Endpoint, dockerClient Docker) {
+	t.Helper()
+	useRemoteHosts(t, &DockerHost{Endpoint: e, client: dockerClient})
+}
+
+// useRemoteHosts registers hosts as the endpoints for the rest of the test.
+func useRemoteHosts(t *testing.T, hosts ...*DockerHost) {
+	t.Helper()
+
+	remoteHostsMu.Lock()
+	defer remoteHostsMu.Unlock()
+
+	remoteHosts = hosts
+	t.Cleanup(func() {
+		remoteHostsMu.Lock()
+		defer remoteHostsMu.Unlock()
+
+		remoteHosts = nil
+	})
+}
+
+func TestCLIRemote(t *testing.T) {
+	remote := newFakeDocker()
+	remote.addContainer("9a8b7c6d5e4f30211203", "shop-api", "shop/api:1.2", container.StateRunning, "Up 4 days", "shop")
+	remote.setLogs("9a8b7c6d5e4f30211203", "serving the production shop\n")
+	useRemoteHost(t, Endpoint{Name: "prod", Host: "ssh://prod.example.com"}, remote)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"ps", []string{"ps"}},
+		{"logs", []string{"logs", "prod/shop-api"}},
+		{"logs stack", []string{"logs", "prod/shop", "--stack"}},
+		{"stacks", []string{"stacks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := strings.Builder{}
+			if err := runCLI(t.Context(), testDocker(), tt.args, &out); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, out.String())
+		})
+	}
+}
+
+func TestCLIUnreachable(t *testing.T) {
+	remote := newFakeDocker()
+	remote.addContainer("9a8b7c6d5e4f30211203", "shop-api", "shop/api:1.2", container.StateRunning, "Up 4 days", "shop")
+	useRemoteHosts(t,
+		&DockerHost{Endpoint: Endpoint{Name: "prod", Host: "ssh://prod.example.com"}, client: remote},
+		// Tried just now, so it isn't tried again during the test
+		&DockerHost{Endpoint: Endpoint{Name: "staging", Host: "ssh://staging.example.com"}, err: errors.New("connection refused"), lastAttempt: time.Now()},
+	)
+
+	out := strings.Builder{}
+	err := runCLI(t.Context(), testDocker(), []string{"ps"}, &out)
+	if err == nil || err.Error() != "staging: connection refused" {
+		t.Errorf("err = %v, want staging: connection refused", err)
+	}
+	golden(t, out.String())
+}
+
+func TestCLIJSON(t *testing.T) {
+	out := strings.Builder{}
+	if err := runCLI(t.Context(), testDocker(), []string{"ps", "--json"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var containers []CLIContainer
+	if err := json.Unmarshal([]byte(out.String()), &containers); err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 3 {
+		t.Fatalf("got %d entries, want nginx, the shop stack and worker", len(containers))
+	}
+	shop := containers[1]
+	if shop.Type != TypeComposeStack || len(shop.Children) != 2 || shop.Children[0].Stack != "shop" {
+		t.Errorf("shop = %+v, want a stack holding its two services", shop)
+	}
+	if shop.Children[1].State != container.StateRunning {
+		t.Errorf("shop-db state = %q, want running", shop.Children[1].State)
+	}
+}
+
+func TestCLIUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown command", []string{"top"}},
+		{"unknown flag", []string{"ps", "--all"}},
+		{"json and format", []string{"ps", "--json", "--format", "{{.Name}}"}},
+		{"logs without a name", []string{"logs"}},
+		{"logs with two names", []string{"logs", "nginx", "worker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCLI(t.Context(), testDocker(), tt.args, &strings.Builder{})
+			if _, ok := err.(usageError); !ok {
+				t.Errorf("error = %v, want a usage error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package src
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const dockerCallTimeout = 10 * time.Second
+
+var (
+	rootCtx    = context.Background()
+	lastViewID atomic.Int64
+)
+
+// SetRootContext sets the context every Docker call derives from, so that
+// cancelling it on quit abandons whatever is still in flight.
+func SetRootContext(ctx context.Context) {
+	rootCtx = ctx
+}
+
+// callCtx bounds a single Docker call by dockerCallTimeout.
+func callCtx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(rootCtx, dockerCallTimeout)
+}
+
+// nextViewID hands every screen instance its own ID. Results of Docker calls
+// carry the ID of the screen that asked for them, so a response that lands
+// after the user navigated elsewhere is dropped instead of applied.
+func nextViewID() int64 {
+	return lastViewID.Add(1)
+}
+
+// actionMsg reports how a mutating call such as starting a container went.
+type actionMsg struct {
+	viewID  int64
+	message string
+	err     error
+}
+
+// actionCmd runs call in the background, reporting message on success.
+func actionCmd(viewID int64, message string, call func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		return actionMsg{viewID: viewID, message: message, err: call(ctx)}
+	}
+}
+
+// pingMsg reports whether a daemon marked unreachable answers again.
+type pingMsg struct {
+	viewID int64
+	err    error
+}
+
+func pingCmd(viewID int64, dockerClient Docker) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(rootCtx, time.Second)
+		defer cancel()
+
+		_, err := dockerClient.Ping(ctx)
+		return pingMsg{viewID: viewID, err: err}
+	}
+}
@@ -0,0 +1,319 @@
+package src
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configPollInterval = 2 * time.Second
+	minRefreshInterval = 100 * time.Millisecond
+)
+
+// Config is the user configuration, read from config.yaml in the
+// stardocker directory under the XDG config dir or from --config. Anything
+// left out keeps its default.
+type Config struct {
+	// RefreshInterval is how often screens poll the daemon.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	// DefaultView is the screen shown once connected: index, containers,
+	// images or networks.
+	DefaultView string               `yaml:"default_view"`
+	Containers  ContainersConfig     `yaml:"containers"`
+	Logs        LogsConfig           `yaml:"logs"`
+	Theme       ThemeConfig          `yaml:"theme"`
+	Themes      map[string]UserTheme `yaml:"themes"`
+	// Keybindings remaps actions by screen, then action name, see keyMaps.
+	Keybindings map[string]map[string][]string `yaml:"keybindings"`
+	// ReadOnly turns off everything that changes anything, as does
+	// --read-only.
+	ReadOnly bool `yaml:"read_only"`
+}
+
+type ContainersConfig struct {
+	// Columns lists the visible columns in order, see containerColumns.
+	Columns []string `yaml:"columns"`
+	// Widths overrides the width of individual columns.
+	Widths map[string]int `yaml:"widths"`
+	// Protected containers have to have their names typed in to be
+	// stopped, restarted or removed.
+	Protected []ProtectedPattern `yaml:"protected"`
+	// Indicators is unicode, ascii for terminals without the glyphs, or
+	// auto to pick after the terminal and locale.
+	Indicators string `yaml:"indicators"`
+	// CrashLoop is when a container dying over and over gets flagged.
+	CrashLoop CrashLoopConfig `yaml:"crash_loop"`
+}
+
+type CrashLoopConfig struct {
+	// Restarts is how many crashes are tolerated within Window.
+	Restarts int           `yaml:"restarts"`
+	Window   time.Duration `yaml:"window"`
+}
+
+type LogsConfig struct {
+	// Since is how far back the log view starts.
+	Since      time.Duration `yaml:"since"`
+	Timestamps bool          `yaml:"timestamps"`
+	// Tail limits the log view to the last lines, "all" for no limit.
+	Tail string `yaml:"tail"`
+}
+
+type ThemeConfig struct {
+	// Name is auto, dark, light, high-contrast or one of the themes
+	// section. auto follows the terminal background.
+	Name string `yaml:"name"`
+	// Colors overrides single colors of the theme.
+	Colors Theme `yaml:"colors"`
+}
+
+var defaultViews = []string{"index", "containers", "images", "networks"}
+
+func DefaultConfig() Config {
+	return Config{
+		RefreshInterval: time.Second,
+		DefaultView:     "index",
+		Containers: ContainersConfig{
+			Columns:    []string{"indicator", "name", "id", "image", "ports", "status", "state", "health", "type"},
+			Indicators: IndicatorsAuto,
+			CrashLoop: CrashLoopConfig{
+				Restarts: 3,
+				Window:   5 * time.Minute,
+			},
+		},
+		Logs: LogsConfig{
+			Since:      24 * time.Hour,
+			Timestamps: true,
+			Tail:       "all",
+		},
+		Theme: ThemeConfig{
+			Name: ThemeAuto,
+		},
+	}
+}
+
+// DefaultConfigPath is $XDG_CONFIG_HOME/stardocker/config.yaml, or the
+// platform equivalent.
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "stardocker", "config.yaml")
+}
+
+// LoadConfig reads and validates the config at path. A missing file gives
+// the defaults.
+func LoadConfig(path string) (Config, error) {
+	c := DefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+
+	// Decode over the defaults, rejecting keys we don't know so typos don't
+	// go unnoticed
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return DefaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return DefaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// Validate reports every problem with c at once.
+func (c Config) Validate() error {
+	errs := []error{}
+
+	if c.RefreshInterval < minRefreshInterval {
+		errs = append(errs, fmt.Errorf("refresh_interval: must be at least %s", minRefreshInterval))
+	}
+
+	if !slices.Contains(defaultViews, c.DefaultView) {
+		errs = append(errs, fmt.Errorf("default_view: %q is not one of %v", c.DefaultView, defaultViews))
+	}
+
+	if len(c.Containers.Columns) == 0 {
+		errs = append(errs, errors.New("containers.columns: at least one column is needed"))
+	}
+	seen := map[string]bool{}
+	for _, name := range c.Containers.Columns {
+		if _, ok := containerColumnByKey(name); !ok {
+			errs = append(errs, fmt.Errorf("containers.columns: unknown column %q", name))
+		}
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("containers.columns: %q is listed twice", name))
+		}
+		seen[name] = true
+	}
+	for name, width := range c.Containers.Widths {
+		if _, ok := containerColumnByKey(name); !ok {
+			errs = append(errs, fmt.Errorf("containers.widths: unknown column %q", name))
+		}
+		if width <= 0 {
+			errs = append(errs, fmt.Errorf("containers.widths.%s: must be positive", name))
+		}
+	}
+
+	for i, p := range c.Containers.Protected {
+		if (p.Name == "") == (p.Label == "") {
+			errs = append(errs, fmt.Errorf("containers.protected[%d]: needs either a name or a label", i))
+		}
+		if _, err := path.Match(p.Name, ""); err != nil {
+			errs = append(errs, fmt.Errorf("containers.protected[%d].name: %q is not a valid pattern", i, p.Name))
+		}
+		if strings.HasPrefix(p.Label, "=") {
+			errs = append(errs, fmt.Errorf("containers.protected[%d].label: %q has no key", i, p.Label))
+		}
+	}
+
+	if !slices.Contains(indicatorModes, c.Containers.Indicators) {
+		errs = append(errs, fmt.Errorf("containers.indicators: %q is not one of %v", c.Containers.Indicators, indicatorModes))
+	}
+	if c.Containers.CrashLoop.Restarts < 1 {
+		errs = append(errs, errors.New("containers.crash_loop.restarts: must be at least 1"))
+	}
+	if c.Containers.CrashLoop.Window <= 0 {
+		errs = append(errs, errors.New("containers.crash_loop.window: must be positive"))
+	}
+
+	if c.Logs.Since < 0 {
+		errs = append(errs, errors.New("logs.since: must not be negative"))
+	}
+	if c.Logs.Tail != "all" {
+		if n, err := strconv.Atoi(c.Logs.Tail); err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("logs.tail: %q is neither \"all\" nor a line count", c.Logs.Tail))
+		}
+	}
+
+	errs = append(errs, validateThemes(c)...)
+
+	errs = append(errs, validateKeybindings(c)...)
+
+	return errors.Join(errs...)
+}
+
+func validColor(color string) bool {
+	if colorPattern.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+var (
+	configMu      sync.RWMutex
+	currentConfig = DefaultConfig()
+	configPath    string
+	readOnlyFlag  atomic.Bool
+)
+
+// SetConfig makes c, read from path, the active configuration. path is
+// watched for changes while the UI runs; it may be empty.
+func SetConfig(path string, c Config) {
+	configMu.Lock()
+	configPath = path
+	configMu.Unlock()
+
+	applyConfig(c)
+}
+
+func applyConfig(c Config) {
+	configMu.Lock()
+	currentConfig = c
+	configMu.Unlock()
+
+	applyTheme(resolveTheme(c))
+}
+
+func CurrentConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return currentConfig
+}
+
+// SetReadOnly turns read-only mode on for the whole session, whatever the
+// config says.
+func SetReadOnly(on bool) {
+	readOnlyFlag.Store(on)
+}
+
+// readOnly tells whether actions that change anything are turned off.
+func readOnly() bool {
+	return readOnlyFlag.Load() || CurrentConfig().ReadOnly
+}
+
+// configMsg is the outcome of looking at the config file. config is nil
+// when the file hasn't changed.
+type configMsg struct {
+	config  *Config
+	modTime time.Time
+	err     error
+}
+
+// configChangedMsg tells every screen a new configuration is in effect.
+type configChangedMsg struct{}
+
+// watchConfigCmd checks the config file for changes made after since.
+func watchConfigCmd(since time.Time) tea.Cmd {
+	configMu.RLock()
+	path := configPath
+	configMu.RUnlock()
+
+	if path == "" {
+		return nil
+	}
+
+	return tea.Tick(configPollInterval, func(time.Time) tea.Msg {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(since) {
+			return configMsg{modTime: since}
+		}
+
+		c, err := LoadConfig(path)
+		if err != nil {
+			return configMsg{modTime: info.ModTime(), err: err}
+		}
+		return configMsg{config: &c, modTime: info.ModTime()}
+	})
+}
+
+// configModTime is when the config file was last written, zero if it
+// doesn't exist yet.
+func configModTime() time.Time {
+	configMu.RLock()
+	path := configPath
+	configMu.RUnlock()
+
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
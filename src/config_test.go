@@ -0,0 +1,208 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useConfig makes c the active config for the rest of the test.
+func useConfig(t *testing.T, c Config) {
+	previous := CurrentConfig()
+	applyConfig(c)
+	t.Cleanup(func() { applyConfig(previous) })
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	c, err := LoadConfig(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RefreshInterval != time.Second || c.Logs.Since != 24*time.Hour {
+		t.Errorf("missing file did not give the defaults: %+v", c)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `
+refresh_interval: 5s
+default_view: containers
+containers:
+  columns: [indicator, name, state]
+  widths:
+    name: 50
+logs:
+  since: 1h
+  tail: "200"
+keybindings:
+  global:
+    back: [esc, h]
+  containers:
+    start_stop: [s]
+`)
+
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RefreshInterval != 5*time.Second {
+		t.Errorf("refresh_interval = %s, want 5s", c.RefreshInterval)
+	}
+	if c.DefaultView != "containers" {
+		t.Errorf("default_view = %q, want containers", c.DefaultView)
+	}
+	if c.Logs.Since != time.Hour || c.Logs.Tail != "200" || !c.Logs.Timestamps {
+		t.Errorf("logs = %+v, want since 1h, tail 200 and the default timestamps", c.Logs)
+	}
+	if got := c.Keybindings["containers"]["start_stop"]; len(got) != 1 || got[0] != "s" {
+		t.Errorf("keybindings.containers.start_stop = %v, want [s]", got)
+	}
+	if c.Theme != DefaultConfig().Theme {
+		t.Errorf("theme = %+v, want the default", c.Theme)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", "refresh: 1s\n", "field refresh not found"},
+		{"refresh too fast", "refresh_interval: 1ms\n", "refresh_interval: must be at least 100ms"},
+		{"unknown view", "default_view: volumes\n", `default_view: "volumes"`},
+		{"unknown column", "containers:\n  columns: [name, disk]\n", `unknown column "disk"`},
+		{"bad tail", "logs:\n  tail: some\n", `logs.tail: "some"`},
+		{"empty protected", "containers:\n  protected:\n    - {}\n", "containers.protected[0]: needs either a name or a label"},
+		{"bad indicators", "containers:\n  indicators: emoji\n", `containers.indicators: "emoji" is not one of [auto unicode ascii]`},
+		{"no crash window", "containers:\n  crash_loop:\n    window: 0s\n", "containers.crash_loop.window: must be positive"},
+		{"bad protected name", "containers:\n  protected:\n    - name: \"db-[\"\n", `containers.protected[0].name: "db-[" is not a valid pattern`},
+		{"bad color", "theme:\n  colors:\n    accent: orange\n", `theme.colors.accent: "orange"`},
+		{"unknown theme", "theme:\n  name: solarized\n", `theme.name: "solarized"`},
+		{"bad base", "themes:\n  mine:\n    base: sepia\n", `themes.mine.base: "sepia"`},
+		{"shadowed theme", "themes:\n  light:\n    accent: \"1\"\n", "themes.light: the name is taken"},
+		{"unknown screen", "keybindings:\n  dashboard:\n    open: [o]\n", `keybindings: unknown screen "dashboard"`},
+		{"unknown action", "keybindings:\n  global:\n    jump: [g]\n", `keybindings.global: unknown action "jump"`},
+		{"no keys", "keybindings:\n  index:\n    select: []\n", "keybindings.index.select: at least one key is needed"},
+		{"clash", "keybindings:\n  containers:\n    start_stop: [q]\n", `keybindings.containers: "q" is bound to both quit and start_stop`},
+		{"clash with global", "keybindings:\n  global:\n    help: [d]\n", `keybindings.network: "d" is bound to both disconnect and help`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigColumns(t *testing.T) {
+	c := DefaultConfig()
+	c.Containers.Columns = []string{"indicator", "name", "state"}
+	c.Containers.Widths = map[string]int{"name": 20}
+	useConfig(t, c)
+
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+	u.golden()
+
+	t.Run("reload", func(t *testing.T) {
+		u.t = t
+		c.Containers.Columns = []string{"name", "image"}
+		useConfig(t, c)
+		u.send(configChangedMsg{})
+		u.golden()
+	})
+}
+
+func TestConfigKeybindings(t *testing.T) {
+	c := DefaultConfig()
+	c.Keybindings = map[string]map[string][]string{"global": {"back": {"h"}}}
+	useConfig(t, c)
+
+	u := newTUI(t, InitIndexModel(testDocker()))
+	u.keys("enter", "esc")
+	if _, ok := u.model.(routerModel).top().(listContainersModel); !ok {
+		t.Fatal("esc went back although back is bound to h only")
+	}
+	u.keys("h")
+	if _, ok := u.model.(routerModel).top().(indexModel); !ok {
+		t.Fatal("h did not go back")
+	}
+
+	t.Run("per screen", func(t *testing.T) {
+		c.Keybindings = map[string]map[string][]string{"containers": {"start_stop": {"s"}}}
+		useConfig(t, c)
+
+		f := testDocker()
+		u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+		u.keys("r", "s", "y")
+		containers, _ := f.ContainerList(t.Context(), container.ListOptions{All: true})
+		for _, c := range containers {
+			if c.ID == "6f1c2b7d9e0a4c3b8a7d" && c.State != container.StateExited {
+				t.Errorf("nginx is %s after pressing s, want exited", c.State)
+			}
+		}
+		u.golden()
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		c.Keybindings = map[string]map[string][]string{"logs": {"top": {"t"}}}
+		useConfig(t, c)
+
+		u := newTUI(t, InitLogsModel(testDocker(), "6f1c2b7d9e0a4c3b8a7d", "nginx"))
+		u.keys("?")
+		u.golden()
+	})
+}
+
+func TestResolveTheme(t *testing.T) {
+	c := DefaultConfig()
+	c.Themes = map[string]UserTheme{
+		"mine": {Base: "light", Theme: Theme{Accent: "#123456"}},
+	}
+	c.Theme = ThemeConfig{Name: "mine", Colors: Theme{Error: "196"}}
+
+	got := resolveTheme(c)
+	want := builtinThemes["light"]
+	want.Accent = "#123456"
+	want.Error = "196"
+	if got != want {
+		t.Errorf("resolveTheme() = %+v, want %+v", got, want)
+	}
+
+	t.Run("auto", func(t *testing.T) {
+		// The tests run with a dark background
+		if got := resolveTheme(DefaultConfig()); got != builtinThemes[ThemeDark] {
+			t.Errorf("auto resolved to %+v, want the dark theme", got)
+		}
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		if got := resolveTheme(c); got != (Theme{}) {
+			t.Errorf("NO_COLOR still has colors: %+v", got)
+		}
+		previous := theme
+		defer applyTheme(previous)
+		applyTheme(resolveTheme(c))
+		if !tableStyles().Selected.GetReverse() {
+			t.Error("without colors the table cursor should be in reverse video")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package src
+
+import (
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Destructive actions ask first. A screen hands the router a confirmMsg
+// with the command to run on yes; until it is answered the dialog covers
+// the screen and takes every key. Containers matching containers.protected
+// in the config have to have their names typed in instead of a plain yes.
+
+// confirmMsg asks the router to confirm before running yes.
+type confirmMsg struct {
+	// question is what is being asked, e.g. "Stop nginx?"
+	question string
+	// details are shown under the question, e.g. the containers concerned
+	details []string
+	// typed is the text to type to confirm, empty for a plain yes
+	typed string
+	yes   tea.Cmd
+}
+
+func confirm(msg confirmMsg) tea.Cmd {
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// confirmDialog is a confirmMsg being answered.
+type confirmDialog struct {
+	confirmMsg
+	input textinput.Model
+	// mismatch is set when enter was pressed on the wrong text
+	mismatch bool
+}
+
+func newConfirmDialog(msg confirmMsg) (confirmDialog, tea.Cmd) {
+	d := confirmDialog{confirmMsg: msg}
+	if msg.typed == "" {
+		return d, nil
+	}
+	d.input = textinput.New()
+	d.input.Prompt = "> "
+	d.input.CharLimit = 256
+	return d, d.input.Focus()
+}
+
+// update feeds a key to the dialog. Once it is answered, cmd is yes if the
+// answer was yes.
+func (d confirmDialog) update(msg tea.KeyMsg) (dialog confirmDialog, answered bool, cmd tea.Cmd) {
+	if msg.Type == tea.KeyEsc {
+		return d, true, nil
+	}
+
+	if d.typed == "" {
+		switch msg.String() {
+		case "y", "enter":
+			return d, true, d.yes
+		case "n":
+			return d, true, nil
+		}
+		return d, false, nil
+	}
+
+	if msg.Type == tea.KeyEnter {
+		if d.input.Value() == d.typed {
+			return d, true, d.yes
+		}
+		d.mismatch = true
+		return d, false, nil
+	}
+	d.mismatch = false
+	d.input, cmd = d.input.Update(msg)
+	return d, false, cmd
+}
+
+func (d confirmDialog) view(width int) string {
+	doc := strings.Builder{}
+
+	doc.WriteString(lipgloss.PlaceHorizontal(width, lipgloss.Left, ContainerTitleStyle.Render("CONFIRM")))
+	doc.WriteString("\n\n")
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(d.question)}
+	if len(d.details) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, d.details...)
+	}
+	lines = append(lines, "")
+	if d.typed == "" {
+		lines = append(lines, "y / enter  yes    n / esc  no")
+	} else {
+		lines = append(lines, `Type "`+d.typed+`" to confirm, esc to cancel.`, d.input.View())
+		if d.mismatch {
+			lines = append(lines, StatusErrorStyle.UnsetMarginLeft().Render("That isn't it."))
+		}
+	}
+	doc.WriteString(DialogStyle.MaxWidth(max(0, width-1)).Render(strings.Join(lines, "\n")))
+
+	return doc.String()
+}
+
+// ProtectedPattern picks out containers that need their name typed in to
+// confirm a destructive action, by name or by label.
+type ProtectedPattern struct {
+	// Name is a shell pattern such as "shared-*".
+	Name string `yaml:"name"`
+	// Label is a key, or key=value.
+	Label string `yaml:"label"`
+}
+
+func (p ProtectedPattern) matches(c Container) bool {
+	if p.Name != "" {
+		if ok, _ := path.Match(p.Name, c.Name); ok {
+			return true
+		}
+	}
+	if p.Label != "" {
+		key, value, hasValue := strings.Cut(p.Label, "=")
+		if v, ok := c.Labels[key]; ok && (!hasValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// protected tells whether the config protects c.
+func protected(c Container) bool {
+	for _, p := range CurrentConfig().Containers.Protected {
+		if p.matches(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,35 +2,200 @@ package src
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	containerTypes "github.com/docker/docker/api/types/container"
-	"github.com/docker/go-sdk/client"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/docker/go-units"
 )
 
-type tickMsg time.Time
+type tickMsg struct {
+	viewID int64
+	time   time.Time
+}
 
 // List Containers Model
 
 type listContainersModel struct {
-	help            help.Model
-	keys            keyMap
-	dockerClient    client.SDKClient
-	width           int
-	height          int
-	table           table.Model
+	keys         containersKeys
+	dockerClient Docker
+	viewID       int64
+	width        int
+	height       int
+	table        table.Model
+	columns      []containerColumn
+	// visible are the columns that fit the window, as laid out
+	visible         []containerColumn
+	fullIDs         bool
+	containers      []Container
+	rows            []containerRow
+	filter          containerFilter
+	sort            containerSort
+	marked          StringSet
+	input           textinput.Model
+	filtering       bool
+	filterErr       error
+	loading         bool
+	status          statusBar
 	ShowChildrenSet StringSet
 }
 
+type containersKeys struct {
+	globalKeys
+	Open      key.Binding
+	Filter    key.Binding
+	Sort      key.Binding
+	Reverse   key.Binding
+	Mark      key.Binding
+	MarkAll   key.Binding
+	Invert    key.Binding
+	FullIDs   key.Binding
+	StartStop key.Binding
+	Restart   key.Binding
+	Remove    key.Binding
+	Contexts  key.Binding
+	Health    key.Binding
+	Exit      key.Binding
+	Processes key.Binding
+	Crashes   key.Binding
+	OpenURL   key.Binding
+	CopyURL   key.Binding
+}
+
+func defaultContainersKeys() containersKeys {
+	return containersKeys{
+		globalKeys: defaultGlobalKeys(),
+		Open: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "logs / expand stack"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort by the next column"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "reverse the sort"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark"),
+		),
+		MarkAll: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "mark all / none"),
+		),
+		Invert: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "invert the marks"),
+		),
+		FullIDs: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "full / short IDs"),
+		),
+		StartStop: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "start / stop"),
+		),
+		Restart: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "restart"),
+		),
+		Remove: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "remove"),
+		),
+		Contexts: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "context"),
+		),
+		Health: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "healthchecks"),
+		),
+		Exit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "why it stopped"),
+		),
+		Processes: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "processes"),
+		),
+		Crashes: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "crash loops"),
+		),
+		OpenURL: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "open port in browser"),
+		),
+		CopyURL: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy port URL"),
+		),
+	}
+}
+
+func newContainersKeys() containersKeys {
+	k := defaultContainersKeys()
+	remap("global", &k.globalKeys)
+	remap("containers", &k)
+	mutating(&k.StartStop, &k.Restart, &k.Remove)
+	return k
+}
+
+func (k *containersKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"open":       &k.Open,
+		"filter":     &k.Filter,
+		"sort":       &k.Sort,
+		"reverse":    &k.Reverse,
+		"mark":       &k.Mark,
+		"mark_all":   &k.MarkAll,
+		"invert":     &k.Invert,
+		"full_ids":   &k.FullIDs,
+		"start_stop": &k.StartStop,
+		"restart":    &k.Restart,
+		"remove":     &k.Remove,
+		"contexts":   &k.Contexts,
+		"health":     &k.Health,
+		"exit":       &k.Exit,
+		"processes":  &k.Processes,
+		"crashes":    &k.Crashes,
+		"open_url":   &k.OpenURL,
+		"copy_url":   &k.CopyURL,
+	}
+}
+
+func (k containersKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Open, k.Filter, k.StartStop, k.Contexts, k.Back, k.Help}
+}
+
+func (k containersKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{
+		{k.Open, k.Filter, k.Sort, k.Reverse, k.FullIDs, k.Contexts},
+		{k.Health, k.Exit, k.Processes, k.Crashes, k.OpenURL, k.CopyURL},
+		{k.Mark, k.MarkAll, k.Invert, k.StartStop, k.Restart, k.Remove},
+	}, k.globalKeys.FullHelp()...)
+}
+
 const composeStackIdentifier = "com.docker.compose.project"
 
 type ContainerType string
@@ -42,75 +207,333 @@ func (ct ContainerType) String() string {
 const (
 	TypeContainer    ContainerType = "container"
 	TypeComposeStack ContainerType = "compose_stack"
+	// TypeUnreachableHost stands in for the containers of an endpoint that
+	// can't be reached
+	TypeUnreachableHost ContainerType = "unreachable_host"
 )
 
-const (
-	ContainerNameIndex  = 1
-	ContainerIDIndex    = 2
-	ContainerStateIndex = 6
-	ContainerTypeIndex  = 7
-)
+// containerColumn is a column the containers table can show. Which ones
+// and in what order comes from containers.columns in the config; which of
+// those fit is up to layoutColumns.
+type containerColumn struct {
+	key   string
+	title string
+	width int
+	// hideRank orders the columns to hide when the window gets narrow,
+	// lowest first; 0 is never hidden
+	hideRank int
+	// flex columns grow into spare room and shrink when there is none
+	flex  bool
+	value func(c Container) string
+}
+
+var containerColumns = []containerColumn{
+	{key: "indicator", title: "⏺", width: 2, value: containerIndicator},
+	{key: "name", title: "Name", width: 35, flex: true, value: func(c Container) string { return c.Name + crashBadge(c) }},
+	{key: "id", title: "Container ID", width: 14, hideRank: 5, value: func(c Container) string { return shortID(c.ID) }},
+	{key: "image", title: "Image", width: 25, hideRank: 9, flex: true, value: func(c Container) string { return c.Image }},
+	{key: "ports", title: "Ports", width: 24, hideRank: 1, flex: true, value: func(c Container) string { return portsString(c.Ports) }},
+	{key: "status", title: "Status", width: 32, hideRank: 10, flex: true, value: func(c Container) string { return c.Status }},
+	{key: "state", title: "State", width: 10, hideRank: 11, value: func(c Container) string { return c.State }},
+	{key: "health", title: "Health", width: 9, hideRank: 6, value: containerHealthColumn},
+	{key: "type", title: "Type", width: 20, hideRank: 2, value: func(c Container) string { return c.Type.String() }},
+	{key: "host", title: "Host", width: 15, hideRank: 4, value: func(c Container) string { return c.Host }},
+	{key: "created", title: "Created", width: 16, hideRank: 3, value: containerCreated},
+	{key: "cpu", title: "CPU %", width: 8, hideRank: 8, value: containerCPU},
+	{key: "mem", title: "Memory", width: 10, hideRank: 7, value: containerMemory},
+}
+
+// statsColumns need CPU and memory figures, which cost a call per
+// container.
+var statsColumns = []string{"cpu", "mem"}
+
+func containerCreated(c Container) string {
+	if c.Created.IsZero() {
+		return ""
+	}
+	return units.HumanDuration(time.Since(c.Created)) + " ago"
+}
+
+func containerCPU(c Container) string {
+	if !hasStats(c) {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", aggregate(c, func(c Container) float64 { return c.CPU }, sum))
+}
+
+func containerMemory(c Container) string {
+	if !hasStats(c) {
+		return ""
+	}
+	return units.BytesSize(float64(aggregate(c, func(c Container) uint64 { return c.Memory }, sum)))
+}
+
+// hasStats tells whether c, or a service of it, has CPU and memory figures.
+func hasStats(c Container) bool {
+	return c.HasStats || slices.ContainsFunc(c.Children, hasStats)
+}
+
+func containerColumnByKey(key string) (containerColumn, bool) {
+	for _, c := range containerColumns {
+		if c.key == key {
+			return c, true
+		}
+	}
+	return containerColumn{}, false
+}
+
+// visibleContainerColumns applies the config to containerColumns. Host is
+// always shown when there is more than one daemon.
+func visibleContainerColumns() []containerColumn {
+	config := CurrentConfig().Containers
+
+	columns := []containerColumn{}
+	for _, key := range config.Columns {
+		c, _ := containerColumnByKey(key)
+		if width, ok := config.Widths[key]; ok {
+			c.width = width
+		}
+		columns = append(columns, c)
+	}
+	if len(RemoteHosts()) > 0 && !slices.Contains(config.Columns, "host") {
+		c, _ := containerColumnByKey("host")
+		columns = append(columns, c)
+	}
+
+	return columns
+}
 
-var tableBaseStyle = lipgloss.NewStyle().
-	BorderStyle(lipgloss.NormalBorder()).
-	BorderForeground(lipgloss.Color("240")).
-	MarginLeft(1)
+// containerRow is what a line of the table stands for: a container, a
+// compose stack, or a container listed under its expanded stack.
+type containerRow struct {
+	Container
+	nested bool
+}
 
 type Container struct {
-	ID       string
-	Name     string
-	Type     ContainerType
-	Image    string
-	Ports    []containerTypes.Port
-	Status   string
-	State    containerTypes.ContainerState
+	ID      string
+	Name    string
+	Type    ContainerType
+	Image   string
+	Ports   []containerTypes.Port
+	Status  string
+	State   containerTypes.ContainerState
+	Labels  map[string]string
+	Created time.Time
+	// CPU is a percentage of one core and Memory is in bytes, both only
+	// known when HasStats
+	CPU      float64
+	Memory   uint64
+	HasStats bool
+	Host     string
 	Children []Container
 }
 
-func InitListContainersModel(dockerClient client.SDKClient, width int, height int) listContainersModel {
-	columns := []table.Column{
-		{Title: "⏺", Width: 2},
-		{Title: "Name", Width: 35},
-		{Title: "Container ID", Width: 15},
-		{Title: "Image", Width: 25},
-		{Title: "Ports", Width: 5},
-		{Title: "Status", Width: 32},
-		{Title: "State", Width: 10},
-		{Title: "Type", Width: 20},
+func init() {
+	registerCommand(command{
+		name: "containers",
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			return push(InitListContainersModel(env.dockerClient, env.width, env.height)), nil
+		},
+	})
+	registerCommand(command{
+		name:     "logs",
+		arg:      "name",
+		complete: completeContainers(nil),
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			dockerClient := env.dockerClient
+			return func() tea.Msg {
+				ctx, cancel := callCtx()
+				defer cancel()
+
+				c, err := FindContainer(ctx, dockerClient, arg)
+				if err != nil {
+					return commandMsg{err: err}
+				}
+				return pushMsg{model: InitLogsModel(dockerClient, c.ID, containerName(c))}
+			}, nil
+		},
+	})
+	registerCommand(command{
+		name:    "stop",
+		arg:     "name",
+		mutates: true,
+		complete: completeContainers(func(c containerTypes.Summary) bool {
+			return c.State == containerTypes.StateRunning
+		}),
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			dockerClient := env.dockerClient
+			return func() tea.Msg {
+				ctx, cancel := callCtx()
+				defer cancel()
+
+				c, err := FindContainer(ctx, dockerClient, arg)
+				if err != nil {
+					return commandMsg{err: err}
+				}
+				stop := func() tea.Msg {
+					ctx, cancel := callCtx()
+					defer cancel()
+
+					if err := StopContainer(ctx, dockerClient, c.ID); err != nil {
+						return commandMsg{err: err}
+					}
+					return commandMsg{message: "Stopped " + containerName(c)}
+				}
+				return confirmContainers(stopAction, []Container{{ID: c.ID, Name: containerName(c), Labels: c.Labels}}, stop)()
+			}, nil
+		},
+	})
+	registerCommand(command{
+		name:     "mark",
+		arg:      "query",
+		complete: completeFilters,
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			filter, err := parseContainerFilter(arg)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := env.top.(listContainersModel); !ok {
+				return nil, errors.New("marking works on the containers screen")
+			}
+			return func() tea.Msg { return markMsg{filter: filter} }, nil
+		},
+	})
+	registerCommand(command{
+		name:     "filter",
+		arg:      "query",
+		optional: true,
+		complete: completeFilters,
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			filter, err := parseContainerFilter(arg)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := env.top.(listContainersModel); ok {
+				return func() tea.Msg { return filterMsg{filter: filter} }, nil
+			}
+			l := InitListContainersModel(env.dockerClient, env.width, env.height)
+			l.filter = filter
+			l.input.SetValue(filter.query)
+			return push(l), nil
+		},
+	})
+}
+
+// filterMsg asks the container list to show only what filter matches.
+type filterMsg struct {
+	filter containerFilter
+}
+
+// markMsg asks the container list to mark what filter matches.
+type markMsg struct {
+	filter containerFilter
+}
+
+// completeContainers completes the names of the containers keep accepts,
+// or all of them when keep is nil.
+func completeContainers(keep func(c containerTypes.Summary) bool) func(ctx context.Context, env commandEnv) []string {
+	return func(ctx context.Context, env commandEnv) []string {
+		containers, err := env.dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+		if err != nil {
+			return nil
+		}
+		names := []string{}
+		for _, c := range containers {
+			if keep == nil || keep(c) {
+				names = append(names, containerName(c))
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+}
+
+// completeFilters suggests a filter for every state and compose project.
+func completeFilters(ctx context.Context, env commandEnv) []string {
+	filters := []string{}
+	for _, state := range []containerTypes.ContainerState{
+		containerTypes.StateRunning,
+		containerTypes.StateExited,
+		containerTypes.StatePaused,
+		containerTypes.StateRestarting,
+		containerTypes.StateCreated,
+		containerTypes.StateDead,
+	} {
+		filters = append(filters, "state="+string(state))
+	}
+	for _, health := range healthOrder {
+		filters = append(filters, "health="+health)
 	}
 
+	containers, err := env.dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+	if err != nil {
+		return filters
+	}
+	projects := StringSet{}
+	for _, c := range containers {
+		if project, ok := c.Labels[composeStackIdentifier]; ok {
+			projects.Add(project)
+		}
+	}
+	for _, project := range slices.Sorted(maps.Keys(projects)) {
+		filters = append(filters, "label="+composeStackIdentifier+"="+project)
+	}
+	return filters
+}
+
+func InitListContainersModel(dockerClient Docker, width int, height int) listContainersModel {
+	columns := visibleContainerColumns()
+
 	t := table.New(
-		table.WithColumns(columns),
 		table.WithFocused(true),
-		table.WithHeight(height-10),
+		table.WithKeyMap(tableKeyMap()),
 	)
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
-
-	return listContainersModel{
-		help:            help.New(),
-		keys:            keys,
+	t.SetStyles(tableStyles())
+
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "name, image, ID or project, state=running, label=key=value"
+	input.CharLimit = 256
+
+	l := listContainersModel{
+		keys:            newContainersKeys(),
+		input:           input,
+		sort:            CurrentState().ContainersSort,
 		dockerClient:    dockerClient,
+		viewID:          nextViewID(),
 		width:           width,
 		height:          height,
 		table:           t,
+		columns:         columns,
+		status:          newStatusBar(),
 		ShowChildrenSet: make(StringSet),
+		marked:          make(StringSet),
 	}
+	l.relayout()
+	l.loading = true
+	l.status.StartLoading()
+
+	return l
+}
+
+type containersMsg struct {
+	viewID     int64
+	containers []Container
+	err        error
 }
 
 func (l listContainersModel) Init() tea.Cmd {
-	l.table.SetRows(l.getRows(FetchContainers(l.dockerClient)))
-	return tea.Batch(tea.SetWindowTitle("Containers"), tickCmd())
+	return tea.Batch(tea.SetWindowTitle("Containers"), l.status.Tick(), l.fetchCmd(), tickCmd(l.viewID))
+}
+
+func (l listContainersModel) client() Docker {
+	return l.dockerClient
+}
+
+func (l listContainersModel) typing() bool {
+	return l.filtering
 }
 
 func (l listContainersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -121,68 +544,292 @@ func (l listContainersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		l.width = msg.Width
 		l.height = msg.Height
+		l.relayout()
 		return l, nil
 
 	case tea.KeyMsg:
-		// Handle help
+		// While typing a filter every key belongs to the input, and the
+		// table follows along
+		if l.filtering {
+			switch msg.Type {
+			case tea.KeyEsc:
+				l.filtering = false
+				l.input.Blur()
+				l.input.Reset()
+				l.setFilter("")
+			case tea.KeyEnter:
+				l.filtering = false
+				l.input.Blur()
+			default:
+				l.input, cmd = l.input.Update(msg)
+				l.setFilter(l.input.Value())
+			}
+			return l, cmd
+		}
+
 		switch {
+
 		case key.Matches(msg, l.keys.Help):
-			l.help.ShowAll = !l.help.ShowAll
-		}
+			return l, showHelp("containers", l.keys)
+
+		// Going back drops the filter first, then the marks
+		case key.Matches(msg, l.keys.Back) && !l.filter.empty():
+			l.input.Reset()
+			l.setFilter("")
+			return l, nil
 
-		switch msg.String() {
+		case key.Matches(msg, l.keys.Back) && len(l.marked) > 0:
+			clear(l.marked)
+			l.setRows()
+			return l, nil
 
-		case "esc":
-			m := InitIndexModel(l.dockerClient)
-			return m, m.Init()
+		case key.Matches(msg, l.keys.Back):
+			return l, pop()
 
-		case "enter":
-			row := l.table.SelectedRow()
-			containerType := strings.TrimSpace(row[ContainerTypeIndex])
+		case key.Matches(msg, l.keys.Filter):
+			l.filtering = true
+			return l, l.input.Focus()
 
-			containerName := strings.TrimSpace(row[ContainerNameIndex])
-			containerID := strings.TrimSpace(row[ContainerIDIndex])
+		case key.Matches(msg, l.keys.FullIDs):
+			l.fullIDs = !l.fullIDs
+			l.relayout()
+			return l, nil
 
-			switch containerType {
+		case key.Matches(msg, l.keys.Sort):
+			cmd = l.setSort(l.sort.next())
+			return l, cmd
 
-			case TypeContainer.String():
-				l := InitLogsModel(l.dockerClient, containerID, containerName)
-				return l, l.Init()
+		case key.Matches(msg, l.keys.Reverse):
+			cmd = l.setSort(l.sort.reversed())
+			return l, cmd
 
-			case TypeComposeStack.String():
-				showChildren := !l.ShowChildrenSet.Contains(row[ContainerNameIndex])
+		case key.Matches(msg, l.keys.Open):
+			row, ok := l.selected()
+			if !ok {
+				return l, nil
+			}
+
+			switch row.Type {
+
+			case TypeContainer:
+				dockerClient, err := l.clientFor(row.Container)
+				if err != nil {
+					l.status.Error(err)
+					return l, nil
+				}
+				return l, push(InitLogsModel(dockerClient, row.ID, row.Name))
+
+			case TypeComposeStack:
+				stack := stackKey(row.Host, row.Name)
+				showChildren := !l.ShowChildrenSet.Contains(stack)
 				if showChildren {
-					l.ShowChildrenSet.Add(row[ContainerNameIndex])
+					l.ShowChildrenSet.Add(stack)
 				} else {
-					l.ShowChildrenSet.Remove(row[ContainerNameIndex])
+					l.ShowChildrenSet.Remove(stack)
 				}
-				allContainers := FetchContainers(l.dockerClient)
-				l.table.SetRows(l.getRows(allContainers))
+				l.setRows()
 			}
 
-		case "q":
+		case key.Matches(msg, l.keys.Quit):
 			return l, tea.Quit
 
-		case "r":
-			row := l.table.SelectedRow()
-			containerID := strings.TrimSpace(row[ContainerIDIndex])
-			containerType := strings.TrimSpace(row[ContainerTypeIndex])
-			containerState := strings.TrimSpace(row[ContainerStateIndex])
+		case key.Matches(msg, l.keys.Contexts):
+			width, height := l.width, l.height
+			return l, push(InitContextsModel(l.dockerClient, width, height, func(dockerClient Docker) []tea.Model {
+				return []tea.Model{InitIndexModel(dockerClient), InitListContainersModel(dockerClient, width, height)}
+			}))
 
-			if containerType == TypeContainer.String() {
-				if containerState == containerTypes.StateRunning {
-					StopContainer(l.dockerClient, containerID)
-				}
-				if containerState == containerTypes.StateExited {
-					StartContainer(l.dockerClient, containerID)
+		case key.Matches(msg, l.keys.Health):
+			row, ok := l.selected()
+			if !ok || row.Type != TypeContainer {
+				return l, nil
+			}
+			return l, push(InitHealthModel(l.dockerClient, row.Container, l.width, l.height))
+
+		case key.Matches(msg, l.keys.Exit):
+			row, ok := l.selected()
+			if !ok || row.Type != TypeContainer {
+				return l, nil
+			}
+			if !stopped(row.Container) {
+				l.status.Info(row.Name + " is " + row.State)
+				return l, nil
+			}
+			return l, push(InitExitModel(l.dockerClient, row.Container, l.width, l.height))
+
+		case key.Matches(msg, l.keys.Processes):
+			row, ok := l.selected()
+			if !ok || row.Type != TypeContainer {
+				return l, nil
+			}
+			if row.State != containerTypes.StateRunning {
+				l.status.Info(row.Name + " is " + row.State)
+				return l, nil
+			}
+			return l, push(InitProcessesModel(l.dockerClient, row.Container, l.width, l.height))
+
+		case key.Matches(msg, l.keys.Crashes):
+			return l, push(InitCrashesModel(l.width, l.height))
+
+		case key.Matches(msg, l.keys.OpenURL), key.Matches(msg, l.keys.CopyURL):
+			row, ok := l.selected()
+			if !ok || row.Type != TypeContainer {
+				return l, nil
+			}
+			url, err := portURL(row.Container)
+			if err == nil && key.Matches(msg, l.keys.CopyURL) {
+				viewID := l.viewID
+				return l, copyText(url, func(err error) tea.Msg {
+					return actionMsg{viewID: viewID, message: "Copied " + url, err: err}
+				})
+			}
+			if err == nil {
+				if err = openURL(url); err == nil {
+					l.status.Info("Opened " + url)
 				}
 			}
+			if err != nil {
+				l.status.Error(err)
+			}
+			return l, nil
+
+		case key.Matches(msg, l.keys.Mark):
+			if row, ok := l.selected(); ok {
+				l.toggleMarks(l.markable([]Container{row.Container}))
+				l.setRows()
+			}
+			return l, nil
+
+		case key.Matches(msg, l.keys.MarkAll):
+			shown := l.markable(l.filter.apply(l.containers))
+			if l.allMarked(shown) {
+				l.unmark(shown)
+			} else {
+				l.mark(shown)
+			}
+			l.setRows()
+			return l, nil
+
+		case key.Matches(msg, l.keys.Invert):
+			for _, c := range l.markable(l.filter.apply(l.containers)) {
+				l.toggleMarks([]Container{c})
+			}
+			l.setRows()
+			return l, nil
+
+		case key.Matches(msg, l.keys.StartStop) && len(l.marked) > 0:
+			action, containers := startOrStop(l.markedContainers())
+			cmd = l.act(action, containers)
+			return l, cmd
+
+		case key.Matches(msg, l.keys.Restart):
+			cmd = l.act(restartAction, l.targets())
+			return l, cmd
+
+		case key.Matches(msg, l.keys.Remove):
+			cmd = l.act(removeAction, l.targets())
+			return l, cmd
+
+		case key.Matches(msg, l.keys.StartStop):
+			row, ok := l.selected()
+			if !ok || row.Type != TypeContainer {
+				return l, nil
+			}
+			switch row.State {
+			case containerTypes.StateRunning:
+				cmd = l.act(stopAction, []Container{row.Container})
+			case containerTypes.StateExited:
+				cmd = l.act(startAction, []Container{row.Container})
+			}
+			return l, cmd
 		}
 
+	case stateSavedMsg:
+		if msg.viewID == l.viewID && msg.err != nil {
+			l.status.Error(fmt.Errorf("could not remember the sort order: %w", msg.err))
+		}
+		return l, nil
+
+	case filterMsg:
+		l.input.SetValue(msg.filter.query)
+		l.setFilter(msg.filter.query)
+		return l, nil
+
+	case markMsg:
+		l.mark(l.markable(msg.filter.apply(l.containers)))
+		l.setRows()
+		message := fmt.Sprintf("Marked what %s matches, %d marked in all", msg.filter.query, len(l.marked))
+		return l, func() tea.Msg { return commandMsg{message: message} }
+
+	case containersMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.loading = false
+		l.status.StopLoading()
+		if msg.err != nil {
+			// Leave the last rows in place
+			l.status.Error(msg.err)
+			return l, nil
+		}
+		l.containers = msg.containers
+		l.pruneMarks()
+		l.setRows()
+		return l, nil
+
+	case bulkMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.status.StopLoading()
+		// What failed stays marked to try again
+		for _, r := range msg.results {
+			if r.err == nil {
+				l.marked.Remove(markKey(r.Container))
+			}
+		}
+		l.setRows()
+		return l, push(InitBulkSummaryModel(msg, l.width, l.height))
+
+	case configChangedMsg:
+		l.keys = newContainersKeys()
+		l.table.KeyMap = tableKeyMap()
+		l.columns = visibleContainerColumns()
+		l.table.SetStyles(tableStyles())
+		l.relayout()
+		return l, nil
+
+	case actionMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.status.StopLoading()
+		if msg.err != nil {
+			l.status.Error(msg.err)
+		} else {
+			l.status.Info(msg.message)
+		}
+		cmd = l.refresh()
+		return l, cmd
+
+	case pingMsg:
+		if msg.viewID != l.viewID || msg.err != nil {
+			return l, nil
+		}
+		l.status.Recovered()
+		cmd = l.refresh()
+		return l, cmd
+
+	case spinner.TickMsg:
+		l.status, cmd = l.status.Update(msg)
+		return l, cmd
+
 	case tickMsg:
-		allContainers := FetchContainers(l.dockerClient)
-		l.table.SetRows(l.getRows(allContainers))
-		return l, tickCmd()
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		cmd = l.refresh()
+		return l, tea.Batch(cmd, tickCmd(l.viewID))
 	}
 
 	l.table, cmd = l.table.Update(msg)
@@ -190,33 +837,161 @@ func (l listContainersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return l, cmd
 }
 
+// refresh asks for a fresh container list unless one is already on its way.
+// While the daemon is unreachable it only pings it.
+func (l *listContainersModel) refresh() tea.Cmd {
+	if l.status.Unreachable() {
+		return pingCmd(l.viewID, l.dockerClient)
+	}
+	if l.loading {
+		return nil
+	}
+	l.loading = true
+	return l.fetchCmd()
+}
+
+func (l listContainersModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, stats := l.viewID, l.dockerClient, l.needsStats()
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		containers, err := FetchAllContainers(ctx, dockerClient)
+		if err == nil && stats {
+			FetchStats(ctx, dockerClient, containers)
+		}
+		return containersMsg{viewID: viewID, containers: containers, err: err}
+	}
+}
+
+// needsStats tells whether the sort or the columns need CPU and memory
+// figures.
+func (l listContainersModel) needsStats() bool {
+	if l.sort.needsStats() {
+		return true
+	}
+	return slices.ContainsFunc(l.visible, func(c containerColumn) bool {
+		return slices.Contains(statsColumns, c.key)
+	})
+}
+
 func (l listContainersModel) View() string {
 	doc := strings.Builder{}
 
-	title := lipgloss.PlaceHorizontal(l.width, lipgloss.Left, ContainerTitleStyle.Render("CONTAINERS"))
+	heading := "CONTAINERS"
+	if !l.filter.empty() {
+		heading += fmt.Sprintf(" · %d of %d · %s", countContainers(l.filter.apply(l.containers)), countContainers(l.containers), l.filter.query)
+	}
+	if l.sort != defaultSort {
+		heading += " · by " + l.sort.String()
+	}
+	if len(l.marked) > 0 {
+		heading += fmt.Sprintf(" · %d marked", len(l.marked))
+	}
+	title := lipgloss.PlaceHorizontal(l.width, lipgloss.Left, ContainerTitleStyle.Render(heading))
 
 	doc.WriteString(title)
 
 	doc.WriteString("\n\n")
 
-	doc.WriteString(tableBaseStyle.Render(l.table.View()) + "\n")
-
-	helpDoc := strings.Builder{}
+	table := l.table.View()
+	if offset, ok := l.indicatorOffset(); ok {
+		table = colorIndicators(table, offset)
+	}
+	table = colorCrashBadges(table)
+	doc.WriteString(TableStyle.Render(table) + "\n")
 
-	helpView := l.help.View(l.keys)
-	height := 8 - strings.Count(helpView, "\n")
+	if l.filtering {
+		line := HelpStyle.Render(l.input.View())
+		if l.filterErr != nil {
+			line += "  " + StatusErrorStyle.Render(l.filterErr.Error())
+		}
+		doc.WriteString(line + "\n")
+	} else if status := l.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
 
-	helpDoc.WriteString(strings.Repeat("\n", height) + helpView)
+	doc.WriteString(helpLine(l.keys))
 
 	return doc.String()
 }
 
-func FetchContainers(dockerClient client.SDKClient) []Container {
-	ctx := context.TODO()
+// FetchAllContainers lists the containers of dockerClient together with
+// those of every endpoint registered with SetEndpoints, grouped by host.
+// Endpoints that can't be reached show up as a single TypeUnreachableHost
+// entry carrying the error in Status.
+func FetchAllContainers(ctx context.Context, dockerClient Docker) ([]Container, error) {
+	allContainers, err := FetchContainers(ctx, dockerClient)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := RemoteHosts()
+	if len(hosts) == 0 {
+		return allContainers, nil
+	}
+
+	for i := range allContainers {
+		allContainers[i].setHost(LocalHostName)
+	}
+
+	for _, h := range hosts {
+		containers, err := func() ([]Container, error) {
+			hostClient, err := h.Client()
+			if err != nil {
+				return nil, err
+			}
+			containers, err := FetchContainers(ctx, hostClient)
+			if err != nil {
+				h.MarkDown(err)
+			}
+			return containers, err
+		}()
+
+		if err != nil {
+			allContainers = append(allContainers, Container{
+				Name:   h.Endpoint.Name,
+				Type:   TypeUnreachableHost,
+				Status: err.Error(),
+				State:  "unreachable",
+				Host:   h.Endpoint.Name,
+			})
+			continue
+		}
+
+		for i := range containers {
+			containers[i].setHost(h.Endpoint.Name)
+		}
+		allContainers = append(allContainers, containers...)
+	}
+
+	// The local daemon comes first, then the endpoints in configuration order
+	order := map[string]int{LocalHostName: 0}
+	for i, h := range hosts {
+		order[h.Endpoint.Name] = i + 1
+	}
+	sort.SliceStable(allContainers, func(i, j int) bool {
+		return order[allContainers[i].Host] < order[allContainers[j].Host]
+	})
+
+	return allContainers, nil
+}
+
+func (c *Container) setHost(host string) {
+	c.Host = host
+	for i := range c.Children {
+		c.Children[i].Host = host
+	}
+}
 
-	containers, err := dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+func FetchContainers(ctx context.Context, dockerClient Docker) ([]Container, error) {
+	var containers []containerTypes.Summary
+	err := retry(ctx, func() (err error) {
+		containers, err = dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+		return err
+	})
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// List to store all containers
@@ -230,23 +1005,27 @@ func FetchContainers(dockerClient client.SDKClient) []Container {
 		if _, ok := container.Labels[composeStackIdentifier]; ok {
 			composeProjectName := container.Labels[composeStackIdentifier]
 			composeContainers[composeProjectName] = append(composeContainers[composeProjectName], Container{
-				ID:     container.ID,
-				Name:   strings.TrimLeft(container.Names[0], "/"),
-				Type:   TypeContainer,
-				Image:  container.Image,
-				Ports:  container.Ports,
-				Status: container.Status,
-				State:  container.State,
+				ID:      container.ID,
+				Name:    strings.TrimLeft(container.Names[0], "/"),
+				Type:    TypeContainer,
+				Image:   container.Image,
+				Ports:   container.Ports,
+				Status:  container.Status,
+				State:   container.State,
+				Labels:  container.Labels,
+				Created: time.Unix(container.Created, 0),
 			})
 		} else {
 			allContainers = append(allContainers, Container{
-				ID:     container.ID,
-				Name:   strings.TrimLeft(container.Names[0], "/"),
-				Image:  container.Image,
-				Ports:  container.Ports,
-				Type:   TypeContainer,
-				Status: container.Status,
-				State:  container.State,
+				ID:      container.ID,
+				Name:    strings.TrimLeft(container.Names[0], "/"),
+				Image:   container.Image,
+				Ports:   container.Ports,
+				Type:    TypeContainer,
+				Status:  container.Status,
+				State:   container.State,
+				Labels:  container.Labels,
+				Created: time.Unix(container.Created, 0),
 			})
 		}
 	}
@@ -254,7 +1033,7 @@ func FetchContainers(dockerClient client.SDKClient) []Container {
 	// Add compose containers to all containers
 	for composeStackName, containers := range composeContainers {
 		composeStack := Container{
-			ID:   primitive.NewObjectID().Hex(),
+			ID:   composeStackID(composeStackName),
 			Name: composeStackName,
 			Type: TypeComposeStack,
 		}
@@ -266,74 +1045,354 @@ func FetchContainers(dockerClient client.SDKClient) []Container {
 
 	sort.Slice(allContainers, func(i, j int) bool { return allContainers[i].Name < allContainers[j].Name })
 
-	return allContainers
+	return allContainers, nil
 }
 
-func (l listContainersModel) getRows(containers []Container) []table.Row {
-	rows := []table.Row{}
+// composeStackID makes up an ID for a compose project. It only depends on
+// the name so it stays the same from one refresh to the next.
+func composeStackID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:12])
+}
 
-	for _, container := range containers {
-		indicator := " "
-		if container.State == containerTypes.StateRunning {
-			indicator = "⏺"
+// setFilter filters the table with query. A query that doesn't parse
+// leaves the last filter in place and reports why.
+func (l *listContainersModel) setFilter(query string) {
+	filter, err := parseContainerFilter(query)
+	l.filterErr = err
+	if err != nil {
+		return
+	}
+	l.filter = filter
+	l.setRows()
+	l.table.SetCursor(0)
+}
+
+// setSort orders the table by s from now on, in later sessions too. The
+// figures for CPU and memory are only fetched once they are needed.
+func (l *listContainersModel) setSort(s containerSort) tea.Cmd {
+	hadStats := l.needsStats()
+	l.sort = s
+	l.setRows()
+
+	cmds := []tea.Cmd{saveStateCmd(l.viewID, func(state *State) { state.ContainersSort = s })}
+	if l.needsStats() && !hadStats {
+		cmds = append(cmds, l.refresh())
+	}
+	return tea.Batch(cmds...)
+}
+
+// relayout fits the columns and the height of the table to the window.
+func (l *listContainersModel) relayout() {
+	columns := slices.Clone(l.columns)
+	if l.fullIDs {
+		for i := range columns {
+			if columns[i].key == "id" {
+				columns[i].width = fullIDWidth
+				columns[i].value = func(c Container) string { return c.ID }
+			}
 		}
-		for _, child := range container.Children {
-			if child.State == containerTypes.StateRunning {
-				indicator = "⏺"
+	}
+	l.visible = layoutColumns(columns, l.width)
+
+	// Drop the rows first, the table can't draw rows narrower than its
+	// columns
+	l.table.SetRows(nil)
+	l.table.SetColumns(tableColumns(l.visible))
+	l.table.SetHeight(max(1, l.height-containersChrome))
+	l.setRows()
+}
+
+// setRows lays l.containers out as table rows, children of expanded stacks
+// right under their stack.
+func (l *listContainersModel) setRows() {
+	l.rows = nil
+	for _, c := range sortContainers(l.filter.apply(l.containers), l.sort) {
+		l.rows = append(l.rows, containerRow{Container: c})
+		// Filtering shows the services that matched under their stack
+		expanded := l.ShowChildrenSet.Contains(stackKey(c.Host, c.Name)) || !l.filter.empty()
+		if c.Type == TypeComposeStack && expanded {
+			for _, child := range c.Children {
+				l.rows = append(l.rows, containerRow{Container: child, nested: true})
 			}
 		}
-		rows = append(rows, table.Row{
-			indicator,
-			container.Name,
-			container.ID,
-			container.Image,
-			fmt.Sprintf("%v", container.Ports),
-			container.Status,
-			container.State,
-			container.Type.String(),
-		})
-		if container.Type == TypeComposeStack && l.ShowChildrenSet.Contains(container.Name) {
-			for _, child := range container.Children {
-				nestedIndicator := " "
-				if strings.TrimSpace(child.State) == containerTypes.StateRunning {
-					nestedIndicator = "⏺"
-				}
-				rows = append(rows, table.Row{
-					nestedIndicator,
-					"  " + child.Name,
-					"  " + child.ID,
-					"  " + child.Image,
-					"  " + fmt.Sprintf("%v", child.Ports),
-					"  " + child.Status,
-					"  " + child.State,
-					"  " + child.Type.String(),
-				})
+	}
+
+	// Marks go in front of the first column after the indicator
+	markColumn := slices.IndexFunc(l.visible, func(c containerColumn) bool { return c.key != "indicator" })
+
+	rows := []table.Row{}
+	for _, r := range l.rows {
+		row := table.Row{}
+		for i, column := range l.visible {
+			value := column.value(r.Container)
+			if i == markColumn && len(l.marked) > 0 {
+				value = l.markOf(r.Container) + " " + value
 			}
+			if r.nested && column.key != "indicator" {
+				value = "  " + value
+			}
+			row = append(row, value)
 		}
+		rows = append(rows, row)
 	}
+	l.table.SetRows(rows)
+}
 
-	return rows
+// markKey identifies a container in marked; IDs are only unique per host.
+func markKey(c Container) string {
+	return stackKey(c.Host, c.ID)
 }
 
-func StartContainer(dockerClient client.SDKClient, containerID string) {
-	ctx := context.Background()
-	err := dockerClient.ContainerStart(ctx, containerID, containerTypes.StartOptions{})
-	if err != nil {
-		panic(err)
+// markable lists the containers that can be marked among containers, the
+// services of stacks included.
+func (l listContainersModel) markable(containers []Container) []Container {
+	markable := []Container{}
+	for _, c := range containers {
+		switch c.Type {
+		case TypeContainer:
+			markable = append(markable, c)
+		case TypeComposeStack:
+			markable = append(markable, c.Children...)
+		}
 	}
+	return markable
 }
 
-func StopContainer(dockerClient client.SDKClient, containerID string) {
-	ctx := context.Background()
-	err := dockerClient.ContainerStop(ctx, containerID, containerTypes.StopOptions{})
+func (l listContainersModel) allMarked(containers []Container) bool {
+	for _, c := range containers {
+		if !l.marked.Contains(markKey(c)) {
+			return false
+		}
+	}
+	return len(containers) > 0
+}
+
+func (l *listContainersModel) mark(containers []Container) {
+	for _, c := range containers {
+		l.marked.Add(markKey(c))
+	}
+}
+
+func (l *listContainersModel) unmark(containers []Container) {
+	for _, c := range containers {
+		l.marked.Remove(markKey(c))
+	}
+}
+
+// toggleMarks unmarks containers when they are all marked and marks them
+// otherwise, so a stack is marked as a whole.
+func (l *listContainersModel) toggleMarks(containers []Container) {
+	if l.allMarked(containers) {
+		l.unmark(containers)
+	} else {
+		l.mark(containers)
+	}
+}
+
+// markOf is "✓" for a marked container or a stack with every service
+// marked, and "-" for a stack with some.
+func (l listContainersModel) markOf(c Container) string {
+	containers := l.markable([]Container{c})
+	switch {
+	case l.allMarked(containers):
+		return "✓"
+	case slices.ContainsFunc(containers, func(c Container) bool { return l.marked.Contains(markKey(c)) }):
+		return "-"
+	}
+	return " "
+}
+
+// markedContainers lists the marked containers, whether the filter shows
+// them or not.
+func (l listContainersModel) markedContainers() []Container {
+	marked := []Container{}
+	for _, c := range l.markable(l.containers) {
+		if l.marked.Contains(markKey(c)) {
+			marked = append(marked, c)
+		}
+	}
+	return marked
+}
+
+// pruneMarks forgets the marks of containers that are gone.
+func (l *listContainersModel) pruneMarks() {
+	present := StringSet{}
+	for _, c := range l.markable(l.containers) {
+		present.Add(markKey(c))
+	}
+	for k := range l.marked {
+		if !present.Contains(k) {
+			l.marked.Remove(k)
+		}
+	}
+}
+
+// targets are the marked containers, or the one under the cursor when
+// none is marked.
+func (l listContainersModel) targets() []Container {
+	if len(l.marked) > 0 {
+		return l.markedContainers()
+	}
+	row, ok := l.selected()
+	if !ok || row.Type != TypeContainer {
+		return nil
+	}
+	return []Container{row.Container}
+}
+
+// act applies action to containers, asking first when it is destructive.
+// While containers are marked it goes through bulkCmd and ends on a
+// summary.
+func (l *listContainersModel) act(action bulkAction, containers []Container) tea.Cmd {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	var run tea.Cmd
+	if len(l.marked) > 0 {
+		run = bulkCmd(l.viewID, l.dockerClient, action, containers)
+	} else {
+		c := containers[0]
+		dockerClient, err := l.clientFor(c)
+		if err != nil {
+			l.status.Error(err)
+			return nil
+		}
+		run = actionCmd(l.viewID, action.done+" "+c.Name, func(ctx context.Context) error {
+			return action.call(ctx, dockerClient, c.ID)
+		})
+	}
+
+	if action.destructive {
+		return confirmContainers(action, containers, run)
+	}
+	return tea.Batch(l.status.StartLoading(), run)
+}
+
+func tableColumns(columns []containerColumn) []table.Column {
+	tableColumns := []table.Column{}
+	for _, c := range columns {
+		title := c.title
+		if c.key == "indicator" {
+			title = runningIndicator.String()
+		}
+		tableColumns = append(tableColumns, table.Column{Title: title, Width: c.width})
+	}
+	return tableColumns
+}
+
+// indicatorOffset is how far into a row the indicator column starts, if
+// it is shown.
+func (l listContainersModel) indicatorOffset() (int, bool) {
+	offset := 0
+	for _, c := range l.visible {
+		if c.key == "indicator" {
+			return offset + cellPadding/2, true
+		}
+		offset += c.width + cellPadding
+	}
+	return 0, false
+}
+
+// selected returns the row under the cursor.
+func (l listContainersModel) selected() (containerRow, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.rows) {
+		return containerRow{}, false
+	}
+	return l.rows[i], true
+}
+
+// clientFor returns the client owning c.
+func (l listContainersModel) clientFor(c Container) (Docker, error) {
+	return hostClient(l.dockerClient, c.Host)
+}
+
+// hostClient is the client for host, local being dockerClient.
+func hostClient(dockerClient Docker, host string) (Docker, error) {
+	if host == "" || host == LocalHostName {
+		return dockerClient, nil
+	}
+
+	h := RemoteHost(host)
+	if h == nil {
+		return nil, fmt.Errorf("unknown host %s", host)
+	}
+	return h.Client()
+}
+
+// stackKey identifies a compose stack in ShowChildrenSet; the same project
+// can be running on more than one host.
+func stackKey(host string, name string) string {
+	if host == "" || host == LocalHostName {
+		return name
+	}
+	return host + "/" + name
+}
+
+func StartContainer(ctx context.Context, dockerClient Docker, containerID string) error {
+	return retry(ctx, func() error {
+		return dockerClient.ContainerStart(ctx, containerID, containerTypes.StartOptions{})
+	})
+}
+
+func StopContainer(ctx context.Context, dockerClient Docker, containerID string) error {
+	return retry(ctx, func() error {
+		return dockerClient.ContainerStop(ctx, containerID, containerTypes.StopOptions{})
+	})
+}
+
+func RestartContainer(ctx context.Context, dockerClient Docker, containerID string) error {
+	return retry(ctx, func() error {
+		return dockerClient.ContainerRestart(ctx, containerID, containerTypes.StopOptions{})
+	})
+}
+
+// RemoveContainer removes a stopped container, keeping its volumes.
+func RemoveContainer(ctx context.Context, dockerClient Docker, containerID string) error {
+	return retry(ctx, func() error {
+		return dockerClient.ContainerRemove(ctx, containerID, containerTypes.RemoveOptions{})
+	})
+}
+
+// FindContainer looks a container up by name or by the start of its ID.
+func FindContainer(ctx context.Context, dockerClient Docker, nameOrID string) (containerTypes.Summary, error) {
+	var containers []containerTypes.Summary
+	err := retry(ctx, func() (err error) {
+		containers, err = dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+		return err
+	})
 	if err != nil {
-		panic(err)
+		return containerTypes.Summary{}, err
+	}
+
+	for _, c := range containers {
+		if containerName(c) == nameOrID {
+			return c, nil
+		}
+	}
+	for _, c := range containers {
+		if strings.HasPrefix(c.ID, nameOrID) {
+			return c, nil
+		}
+	}
+	return containerTypes.Summary{}, fmt.Errorf("no container named %q", nameOrID)
+}
+
+func containerName(c containerTypes.Summary) string {
+	if len(c.Names) == 0 {
+		return c.ID
 	}
+	return strings.TrimLeft(c.Names[0], "/")
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second*1, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// tickCmd drives the periodic refresh of the view with viewID, every
+// refresh_interval. Ticks are tagged so a screen that is no longer on top
+// lets its loop die out instead of feeding the one that is.
+func tickCmd(viewID int64) tea.Cmd {
+	return tea.Tick(CurrentConfig().RefreshInterval, func(t time.Time) tea.Msg {
+		return tickMsg{viewID: viewID, time: t}
 	})
 }
 
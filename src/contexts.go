@@ -0,0 +1,362 @@
+package src
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	dockerClientLib "github.com/docker/docker/client"
+	"github.com/docker/go-sdk/client"
+	"github.com/docker/go-sdk/config"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// DockerContext is a daemon endpoint from the Docker CLI configuration.
+type DockerContext struct {
+	Name        string
+	Host        string
+	Description string
+	Current     bool
+}
+
+// ListDockerContexts returns the default context followed by every context
+// in the Docker config, marking the one DOCKER_HOST/DOCKER_CONTEXT or the
+// config currently point at.
+func ListDockerContexts() ([]DockerContext, error) {
+	current, err := dockercontext.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := dockercontext.List()
+	if err != nil {
+		// A missing contexts directory just means only the default exists
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	sort.Strings(names)
+
+	defaultHost := os.Getenv(dockercontext.EnvOverrideHost)
+	if defaultHost == "" {
+		defaultHost = dockercontext.DefaultDockerHost
+	}
+
+	contexts := []DockerContext{{
+		Name:        dockercontext.DefaultContextName,
+		Host:        defaultHost,
+		Description: "DOCKER_HOST or the platform default socket",
+		Current:     current == dockercontext.DefaultContextName,
+	}}
+
+	for _, name := range names {
+		if name == dockercontext.DefaultContextName {
+			continue
+		}
+		c := DockerContext{Name: name, Current: current == name}
+		if inspected, err := dockercontext.Inspect(name); err == nil {
+			if endpoint, ok := inspected.Endpoints["docker"]; ok {
+				c.Host = endpoint.Host
+			}
+			if inspected.Metadata != nil {
+				c.Description = inspected.Metadata.Description
+			}
+		}
+		contexts = append(contexts, c)
+	}
+
+	return contexts, nil
+}
+
+// NewDockerClient connects to the daemon behind contextName. An empty name
+// follows the same rules as the docker CLI: DOCKER_HOST, then
+// DOCKER_CONTEXT, then the current context from the config.
+func NewDockerClient(ctx context.Context, contextName string) (Docker, error) {
+	if contextName == "" {
+		return client.New(ctx)
+	}
+
+	// The SDK lets DOCKER_HOST override whatever context it is given, so an
+	// explicitly chosen context gets its own API client, built the same way
+	// as an endpoint's
+	e, err := contextEndpoint(contextName)
+	if err != nil {
+		return nil, err
+	}
+	return NewEndpointClient(ctx, e)
+}
+
+// contextEndpoint resolves a Docker context to its daemon and the directory
+// the docker CLI keeps the context's certificates in, if it has any.
+func contextEndpoint(contextName string) (Endpoint, error) {
+	e := Endpoint{Name: contextName}
+
+	if contextName == dockercontext.DefaultContextName {
+		e.Host = os.Getenv(dockercontext.EnvOverrideHost)
+		if e.Host == "" {
+			e.Host = dockercontext.DefaultDockerHost
+		}
+		e.CertPath = os.Getenv(dockerClientLib.EnvOverrideCertPath)
+		return e, nil
+	}
+
+	host, err := dockercontext.DockerHostFromContext(contextName)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	e.Host = host
+
+	dir, err := config.Dir()
+	if err != nil {
+		return Endpoint{}, err
+	}
+	certPath := filepath.Join(dir, "contexts", "tls", fmt.Sprintf("%x", sha256.Sum256([]byte(contextName))), "docker")
+	if _, err := os.Stat(certPath); err == nil {
+		e.CertPath = certPath
+	}
+
+	return e, nil
+}
+
+type contextSwitchMsg struct {
+	contextName  string
+	dockerClient Docker
+	err          error
+}
+
+// Contexts Model
+
+type contextsModel struct {
+	keys         contextsKeys
+	dockerClient Docker
+	width        int
+	height       int
+	table        table.Model
+	rebuild      func(dockerClient Docker) []tea.Model
+	switching    string
+	status       statusBar
+}
+
+const ContextNameIndex = 1
+
+type contextsKeys struct {
+	globalKeys
+	Switch key.Binding
+}
+
+func defaultContextsKeys() contextsKeys {
+	return contextsKeys{
+		globalKeys: defaultGlobalKeys(),
+		Switch: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "switch"),
+		),
+	}
+}
+
+func newContextsKeys() contextsKeys {
+	k := defaultContextsKeys()
+	remap("global", &k.globalKeys)
+	remap("contexts", &k)
+	return k
+}
+
+func (k *contextsKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"switch": &k.Switch,
+	}
+}
+
+func (k contextsKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Switch, k.Back, k.Help}
+}
+
+func (k contextsKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Switch}}, k.globalKeys.FullHelp()...)
+}
+
+// InitContextsModel opens the context switcher. rebuild recreates the
+// screens below it against the new client after a switch, the last one
+// being shown.
+func InitContextsModel(dockerClient Docker, width int, height int, rebuild func(dockerClient Docker) []tea.Model) contextsModel {
+	columns := []table.Column{
+		{Title: " ", Width: 2},
+		{Title: "Name", Width: 25},
+		{Title: "Docker Endpoint", Width: 45},
+		{Title: "Description", Width: 40},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeyMap()),
+		table.WithHeight(max(5, height-10)),
+	)
+
+	t.SetStyles(tableStyles())
+
+	c := contextsModel{
+		keys:         newContextsKeys(),
+		dockerClient: dockerClient,
+		width:        width,
+		height:       height,
+		table:        t,
+		rebuild:      rebuild,
+		status:       newStatusBar(),
+	}
+
+	contexts, err := ListDockerContexts()
+	if err != nil {
+		c.status.Error(err)
+	}
+
+	// Mark the context whose endpoint we're actually talking to, which may
+	// differ from the CLI's current context after switching in here
+	activeHost := dockerClient.DaemonHost()
+	rows := []table.Row{}
+	for _, dc := range contexts {
+		marker := " "
+		if dc.Host == activeHost {
+			marker = "*"
+		}
+		rows = append(rows, table.Row{marker, dc.Name, dc.Host, dc.Description})
+	}
+	c.table.SetRows(rows)
+
+	return c
+}
+
+func (c contextsModel) Init() tea.Cmd {
+	return tea.SetWindowTitle("Docker Contexts")
+}
+
+func (c contextsModel) client() Docker {
+	return c.dockerClient
+}
+
+func (c contextsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		c.keys = newContextsKeys()
+		c.table.KeyMap = tableKeyMap()
+		c.table.SetStyles(tableStyles())
+		return c, nil
+
+	case tea.WindowSizeMsg:
+		c.width = msg.Width
+		c.height = msg.Height
+		return c, nil
+
+	case contextSwitchMsg:
+		c.switching = ""
+		if msg.err != nil {
+			c.status.Error(fmt.Errorf("could not switch to %s: %w", msg.contextName, msg.err))
+			return c, nil
+		}
+		return c, reset(c.dockerClient, c.rebuild(msg.dockerClient)...)
+
+	case tea.KeyMsg:
+		// Ignore input while a switch is in flight
+		if c.switching != "" {
+			return c, nil
+		}
+
+		switch {
+
+		case key.Matches(msg, c.keys.Help):
+			return c, showHelp("contexts", c.keys)
+
+		case key.Matches(msg, c.keys.Back):
+			return c, pop()
+
+		case key.Matches(msg, c.keys.Quit):
+			return c, tea.Quit
+
+		case key.Matches(msg, c.keys.Switch):
+			row := c.table.SelectedRow()
+			if row == nil {
+				return c, nil
+			}
+			c.switching = row[ContextNameIndex]
+			return c, switchContextCmd(row[ContextNameIndex])
+		}
+	}
+
+	c.table, cmd = c.table.Update(msg)
+
+	return c, cmd
+}
+
+func (c contextsModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(c.width, lipgloss.Left, ContainerTitleStyle.Render("DOCKER CONTEXTS"))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	doc.WriteString(TableStyle.Render(c.table.View()) + "\n")
+
+	if c.switching != "" {
+		doc.WriteString(HelpStyle.Render("Connecting to "+c.switching+"...") + "\n")
+	} else if status := c.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(c.keys))
+
+	return doc.String()
+}
+
+func init() {
+	registerCommand(command{
+		name: "context",
+		arg:  "name",
+		complete: func(ctx context.Context, env commandEnv) []string {
+			contexts, err := ListDockerContexts()
+			if err != nil {
+				return nil
+			}
+			names := []string{}
+			for _, c := range contexts {
+				names = append(names, c.Name)
+			}
+			return names
+		},
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			old, width, height := env.dockerClient, env.width, env.height
+			return func() tea.Msg {
+				ctx, cancel := callCtx()
+				defer cancel()
+
+				dockerClient, err := NewDockerClient(ctx, arg)
+				if err != nil {
+					return commandMsg{err: fmt.Errorf("could not switch to %s: %w", arg, err)}
+				}
+				return resetMsg{stack: []tea.Model{InitIndexModel(dockerClient), InitListContainersModel(dockerClient, width, height)}, old: old}
+			}, nil
+		},
+	})
+}
+
+func switchContextCmd(contextName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		dockerClient, err := NewDockerClient(ctx, contextName)
+		return contextSwitchMsg{contextName: contextName, dockerClient: dockerClient, err: err}
+	}
+}
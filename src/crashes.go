@@ -0,0 +1,412 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/go-units"
+)
+
+// Crash loops are caught from the daemon's events for the whole session,
+// whichever screen is showing: the router follows the containers dying on
+// the daemon of its screens and on every endpoint, and a container that
+// died more than containers.crash_loop.restarts times within
+// crash_loop.window is flagged in the containers table, counted in the top
+// line of every screen and listed by the crashes screen. A death that
+// follows a kill, as docker stop and docker restart do, isn't a crash.
+// Only the deaths seen while running count: the restart count a container
+// carries has no times to place its restarts in the window.
+
+// killGrace is how long after a kill a container dying is taken to be
+// stopped rather than crashed; docker stop waits 10s before a SIGKILL.
+const killGrace = 30 * time.Second
+
+// maxCrashes is how many crashes are remembered per container.
+const maxCrashes = 20
+
+// crashWatchRetry is how long to wait before following events again when
+// the stream broke.
+const crashWatchRetry = 5 * time.Second
+
+type crash struct {
+	At       time.Time
+	ExitCode int
+}
+
+// crashHistory is what is known of the deaths of one container.
+type crashHistory struct {
+	// Host is the endpoint the container runs on, or LocalHostName
+	Host    string
+	ID      string
+	Name    string
+	Crashes []crash
+	// killed is when the container was last killed on purpose
+	killed time.Time
+}
+
+// recent are the crashes within window of now, newest first.
+func (h crashHistory) recent(now time.Time, window time.Duration) []crash {
+	recent := []crash{}
+	for _, c := range slices.Backward(h.Crashes) {
+		if now.Sub(c.At) <= window {
+			recent = append(recent, c)
+		}
+	}
+	return recent
+}
+
+type crashTracker struct {
+	mu        sync.Mutex
+	histories map[string]*crashHistory
+}
+
+var crashes = &crashTracker{histories: map[string]*crashHistory{}}
+
+// record takes in a container event from the daemon of host, returning
+// whether it was a crash.
+func (t *crashTracker) record(host string, event events.Message) bool {
+	if event.Type != events.ContainerEventType {
+		return false
+	}
+	at := time.Unix(0, event.TimeNano)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Container IDs are only unique on their own daemon
+	id := stackKey(host, event.Actor.ID)
+	h, ok := t.histories[id]
+	if !ok {
+		h = &crashHistory{Host: host, ID: event.Actor.ID}
+		t.histories[id] = h
+	}
+	if name := event.Actor.Attributes["name"]; name != "" {
+		h.Name = name
+	}
+
+	switch event.Action {
+	case events.ActionKill:
+		h.killed = at
+	case events.ActionDie:
+		if !h.killed.IsZero() && at.Sub(h.killed) <= killGrace {
+			return false
+		}
+		exitCode, _ := strconv.Atoi(event.Actor.Attributes["exitCode"])
+		h.Crashes = append(h.Crashes, crash{At: at, ExitCode: exitCode})
+		if len(h.Crashes) > maxCrashes {
+			h.Crashes = h.Crashes[len(h.Crashes)-maxCrashes:]
+		}
+		return true
+	}
+	return false
+}
+
+// loops lists the containers in a crash loop, the latest crash first.
+func (t *crashTracker) loops(now time.Time) []crashHistory {
+	config := CurrentConfig().Containers.CrashLoop
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	loops := []crashHistory{}
+	for _, h := range t.histories {
+		if recent := h.recent(now, config.Window); len(recent) > config.Restarts {
+			loop := *h
+			loop.Crashes = recent
+			loops = append(loops, loop)
+		}
+	}
+	slices.SortFunc(loops, func(a, b crashHistory) int {
+		return b.Crashes[0].At.Compare(a.Crashes[0].At)
+	})
+	return loops
+}
+
+// loop is the crash loop containerID on host is in, if it is in one.
+func (t *crashTracker) loop(host string, containerID string, now time.Time) (crashHistory, bool) {
+	for _, h := range t.loops(now) {
+		if h.ID == containerID && stackKey(h.Host, "") == stackKey(host, "") {
+			return h, true
+		}
+	}
+	return crashHistory{}, false
+}
+
+func (t *crashTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.histories)
+}
+
+// crashBadge is shown after the name of a container in a crash loop.
+func crashBadge(c Container) string {
+	if c.Type != TypeContainer {
+		return ""
+	}
+	h, ok := crashes.loop(c.Host, c.ID, time.Now())
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" %s %d crashes", alertGlyph(), len(h.Crashes))
+}
+
+var crashBadgePattern = regexp.MustCompile(`[▲!] \d+ crashes`)
+
+// colorCrashBadges draws the crash badges of a rendered table in the
+// warning color, leaving the row under the cursor alone as
+// colorIndicators does.
+func colorCrashBadges(view string) string {
+	badge := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Warning))
+	lines := strings.Split(view, "\n")
+	for n, line := range lines {
+		if n < 2 || strings.Contains(line, "\x1b[") {
+			continue
+		}
+		lines[n] = crashBadgePattern.ReplaceAllStringFunc(line, func(b string) string { return badge.Render(b) })
+	}
+	return strings.Join(lines, "\n")
+}
+
+func alertGlyph() string {
+	if asciiIndicators() {
+		return "!"
+	}
+	return "▲"
+}
+
+// withAlerts puts the number of crash loops at the end of the top line of
+// view, if there is room.
+func withAlerts(view string, width int) string {
+	n := len(crashes.loops(time.Now()))
+	if n == 0 {
+		return view
+	}
+	alert := fmt.Sprintf("%s %d crash loops", alertGlyph(), n)
+	if n == 1 {
+		alert = alertGlyph() + " 1 crash loop"
+	}
+	alert = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Warning)).Bold(true).Render(alert)
+
+	first, rest, _ := strings.Cut(view, "\n")
+	first = strings.TrimRight(first, " ")
+	pad := width - lipgloss.Width(first) - lipgloss.Width(alert) - 1
+	if pad < 1 {
+		return view
+	}
+	return first + strings.Repeat(" ", pad) + alert + " \n" + rest
+}
+
+// crashSubscription is the stream of container deaths of the daemon of
+// host, through client.
+type crashSubscription struct {
+	host     string
+	client   Docker
+	cancel   context.CancelFunc
+	messages <-chan events.Message
+	errs     <-chan error
+}
+
+type crashEventMsg struct {
+	sub   *crashSubscription
+	event events.Message
+}
+
+type crashWatchEndedMsg struct {
+	sub *crashSubscription
+	err error
+}
+
+// watchCrashesMsg asks the router to follow the events, when it isn't
+// already.
+type watchCrashesMsg struct{}
+
+func subscribeCrashes(host string, dockerClient Docker) *crashSubscription {
+	ctx, cancel := context.WithCancel(rootCtx)
+	messages, errs := dockerClient.Events(ctx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("event", string(events.ActionDie)),
+			filters.Arg("event", string(events.ActionKill)),
+		),
+	})
+	return &crashSubscription{host: host, client: dockerClient, cancel: cancel, messages: messages, errs: errs}
+}
+
+// next waits for the next event of sub.
+func (sub *crashSubscription) next() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event, ok := <-sub.messages:
+			if !ok {
+				return crashWatchEndedMsg{sub: sub}
+			}
+			return crashEventMsg{sub: sub, event: event}
+		case err := <-sub.errs:
+			return crashWatchEndedMsg{sub: sub, err: err}
+		}
+	}
+}
+
+func init() {
+	registerCommand(command{
+		name: "crashes",
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			return push(InitCrashesModel(env.width, env.height)), nil
+		},
+	})
+}
+
+// Crashes Model
+
+// crashesChrome is the height of the crashes screen around its table, a
+// line more than the containers table for the note under the heading.
+const crashesChrome = containersChrome + 1
+
+// crashesModel lists the containers in a crash loop with the times they
+// crashed.
+type crashesModel struct {
+	keys   scrollKeys
+	viewID int64
+	width  int
+	height int
+	table  table.Model
+}
+
+func InitCrashesModel(width int, height int) crashesModel {
+	keys := newScrollKeys("crashes")
+	t := table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(keys.tableKeyMap()),
+	)
+	t.SetStyles(tableStyles())
+
+	c := crashesModel{
+		keys:   keys,
+		viewID: nextViewID(),
+		width:  width,
+		height: height,
+		table:  t,
+	}
+	c.resize()
+	c.setRows()
+
+	return c
+}
+
+func (c crashesModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Crashes"), tickCmd(c.viewID))
+}
+
+func (c crashesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		c.keys = newScrollKeys("crashes")
+		c.table.KeyMap = c.keys.tableKeyMap()
+		c.table.SetStyles(tableStyles())
+		c.setRows()
+		return c, nil
+
+	case tea.WindowSizeMsg:
+		c.width = msg.Width
+		c.height = msg.Height
+		c.resize()
+		c.setRows()
+		return c, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, c.keys.Help):
+			return c, showHelp("crashes", c.keys)
+		case key.Matches(msg, c.keys.Back):
+			return c, pop()
+		case key.Matches(msg, c.keys.Quit):
+			return c, tea.Quit
+		}
+
+	case tickMsg:
+		if msg.viewID != c.viewID {
+			return c, nil
+		}
+		c.setRows()
+		return c, tickCmd(c.viewID)
+	}
+
+	c.table, cmd = c.table.Update(msg)
+
+	return c, cmd
+}
+
+func (c *crashesModel) resize() {
+	columns := []table.Column{
+		{Title: "Container", Width: 30},
+		{Title: "Crashed", Width: 20},
+		{Title: "Exit", Width: 4},
+		{Title: "Meaning", Width: 0},
+	}
+	used := tableChrome
+	for _, column := range columns[:len(columns)-1] {
+		used += column.Width + cellPadding
+	}
+	columns[len(columns)-1].Width = max(minFlexWidth, c.width-used-cellPadding)
+
+	c.table.SetRows(nil)
+	c.table.SetColumns(columns)
+	c.table.SetHeight(max(1, c.height-crashesChrome))
+}
+
+// setRows lists every recent crash, grouped by container.
+func (c *crashesModel) setRows() {
+	now := time.Now()
+	rows := []table.Row{}
+	for _, h := range crashes.loops(now) {
+		for i, crashed := range h.Crashes {
+			name := ""
+			if i == 0 {
+				name = stackKey(h.Host, h.Name)
+			}
+			rows = append(rows, table.Row{
+				name,
+				units.HumanDuration(now.Sub(crashed.At)) + " ago",
+				strconv.Itoa(crashed.ExitCode),
+				exitMeaning(crashed.ExitCode, false),
+			})
+		}
+	}
+	c.table.SetRows(rows)
+}
+
+func (c crashesModel) View() string {
+	doc := strings.Builder{}
+
+	config := CurrentConfig().Containers.CrashLoop
+	heading := fmt.Sprintf("CRASH LOOPS · more than %d crashes in %s", config.Restarts, config.Window)
+	doc.WriteString(lipgloss.PlaceHorizontal(c.width, lipgloss.Left, ContainerTitleStyle.Render(heading)))
+	doc.WriteString("\n")
+	doc.WriteString(HelpStyle.Render("Counted from the crashes seen since stardocker started.") + "\n\n")
+
+	if len(c.table.Rows()) == 0 {
+		doc.WriteString(HelpStyle.Render("No container is crashing over and over.") + "\n\n")
+	} else {
+		doc.WriteString(TableStyle.Render(c.table.View()) + "\n")
+	}
+
+	doc.WriteString(helpLine(c.keys))
+
+	return doc.String()
+}
@@ -0,0 +1,77 @@
+package src
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+// containerEvent is what the daemon sends when containerID does action.
+func containerEvent(action events.Action, containerID string, name string, exitCode int, at time.Time) events.Message {
+	attributes := map[string]string{"name": name}
+	if action == events.ActionDie {
+		attributes["exitCode"] = strconv.Itoa(exitCode)
+	}
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   action,
+		Actor:    events.Actor{ID: containerID, Attributes: attributes},
+		TimeNano: at.UnixNano(),
+	}
+}
+
+func useCrashes(t *testing.T) {
+	crashes.reset()
+	t.Cleanup(crashes.reset)
+}
+
+func TestCrashTracker(t *testing.T) {
+	useCrashes(t)
+	now := time.Now()
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	// Three crashes in the window, one before it and one stop
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 1, ago(10*time.Minute)))
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 1, ago(4*time.Minute)))
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 2, ago(3*time.Minute)))
+	crashes.record(LocalHostName, containerEvent(events.ActionKill, "api", "api", 0, ago(2*time.Minute)))
+	if crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 143, ago(2*time.Minute-5*time.Second))) {
+		t.Error("dying after a kill counted as a crash")
+	}
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 139, ago(time.Minute)))
+
+	if loops := crashes.loops(now); len(loops) != 0 {
+		t.Errorf("3 crashes in 5m are a loop: %+v", loops)
+	}
+
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "api", "api", 1, ago(30*time.Second)))
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "db", "db", 1, ago(2*time.Minute)))
+	crashes.record(LocalHostName, containerEvent(events.ActionDie, "db", "db", 1, ago(time.Minute)))
+
+	loops := crashes.loops(now)
+	if len(loops) != 1 || loops[0].Name != "api" {
+		t.Fatalf("loops = %+v, want api only", loops)
+	}
+	codes := []int{}
+	for _, c := range loops[0].Crashes {
+		codes = append(codes, c.ExitCode)
+	}
+	if want := []int{1, 139, 2, 1}; !slices.Equal(codes, want) {
+		t.Errorf("recent exit codes = %v, want %v", codes, want)
+	}
+
+	// The loop is over once the crashes fall out of the window
+	if loops := crashes.loops(now.Add(5 * time.Minute)); len(loops) != 0 {
+		t.Errorf("loops 5m later = %+v", loops)
+	}
+
+	c := DefaultConfig()
+	c.Containers.CrashLoop.Restarts = 1
+	useConfig(t, c)
+	if loops := crashes.loops(now); len(loops) != 2 {
+		t.Errorf("with 1 restart allowed, loops = %+v", loops)
+	}
+}
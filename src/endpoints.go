@@ -0,0 +1,308 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	dockerClientLib "github.com/docker/docker/client"
+	"github.com/docker/go-sdk/client"
+)
+
+const (
+	// LocalHostName labels the daemon the rest of the app is connected to
+	// when several endpoints are shown together.
+	LocalHostName = "local"
+
+	endpointConnectTimeout = 5 * time.Second
+	endpointRetryInterval  = 30 * time.Second
+)
+
+// Endpoint is an extra daemon configured with --endpoint. Host is either a
+// Docker context name or a daemon URL (unix://, tcp://, ssh://).
+type Endpoint struct {
+	Name     string
+	Host     string
+	CertPath string
+}
+
+// ParseEndpoint reads "name=host[,tls=certdir]". The name is optional and
+// defaults to the host. certdir holds ca.pem, cert.pem and key.pem, the same
+// layout as DOCKER_CERT_PATH.
+func ParseEndpoint(spec string) (Endpoint, error) {
+	e := Endpoint{}
+
+	parts := strings.Split(spec, ",")
+	target := parts[0]
+	for _, option := range parts[1:] {
+		k, v, ok := strings.Cut(option, "=")
+		if !ok || k != "tls" {
+			return Endpoint{}, fmt.Errorf("endpoint %q: unknown option %q", spec, option)
+		}
+		e.CertPath = v
+	}
+
+	if name, host, ok := strings.Cut(target, "="); ok {
+		e.Name, e.Host = name, host
+	} else {
+		e.Name, e.Host = target, target
+	}
+
+	if e.Name == "" || e.Host == "" {
+		return Endpoint{}, fmt.Errorf("endpoint %q: expected name=host", spec)
+	}
+	if e.Name == LocalHostName {
+		return Endpoint{}, fmt.Errorf("endpoint %q: the name %q is reserved", spec, LocalHostName)
+	}
+
+	return e, nil
+}
+
+// NewEndpointClient connects to a single endpoint.
+func NewEndpointClient(ctx context.Context, e Endpoint) (Docker, error) {
+	if !strings.Contains(e.Host, "://") {
+		resolved, err := contextEndpoint(e.Host)
+		if err != nil {
+			return nil, err
+		}
+		e.Host = resolved.Host
+		if e.CertPath == "" {
+			e.CertPath = resolved.CertPath
+		}
+	}
+
+	opts := []dockerClientLib.Opt{dockerClientLib.WithAPIVersionNegotiation()}
+
+	u, err := url.Parse(e.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "ssh" {
+		// Same trick as the docker CLI: run `docker system dial-stdio` on the
+		// remote end and speak HTTP over the ssh session
+		opts = append(opts,
+			dockerClientLib.WithHost("http://docker.example.com"),
+			dockerClientLib.WithDialContext(sshDialer(u)),
+		)
+	} else {
+		opts = append(opts, dockerClientLib.WithHost(e.Host))
+	}
+
+	if e.CertPath != "" {
+		opts = append(opts, dockerClientLib.WithTLSClientConfig(
+			certFile(e.CertPath, "ca.pem"),
+			certFile(e.CertPath, "cert.pem"),
+			certFile(e.CertPath, "key.pem"),
+		))
+	}
+
+	api, err := dockerClientLib.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(ctx, client.WithDockerAPI(api))
+}
+
+// certFile is name in dir, or nothing when dir doesn't hold it: a context
+// may carry a CA without a client certificate, or the other way round.
+func certFile(dir string, name string) string {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+func sshDialer(u *url.URL) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	// BatchMode fails straight away where ssh would ask for a password or
+	// to trust a new host key, neither of which can be answered from here
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", int(endpointConnectTimeout.Seconds())),
+	}
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+	if u.Port() != "" {
+		args = append(args, "-p", u.Port())
+	}
+	args = append(args, "--", u.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		cmd := exec.CommandContext(ctx, "ssh", args...)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+	}
+}
+
+// commandConn is a net.Conn over a subprocess's stdin and stdout.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	once   sync.Once
+}
+
+func (c *commandConn) Read(p []byte) (int, error)  { return c.stdout.Read(p) }
+func (c *commandConn) Write(p []byte) (int, error) { return c.stdin.Write(p) }
+
+func (c *commandConn) Close() error {
+	c.once.Do(func() {
+		c.stdin.Close()
+		c.stdout.Close()
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr                { return dummyAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr               { return dummyAddr{} }
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type dummyAddr struct{}
+
+func (dummyAddr) Network() string { return "ssh" }
+func (dummyAddr) String() string  { return "ssh" }
+
+// DockerHost is one configured endpoint and its connection. client is nil
+// while the endpoint is unreachable, in which case err says why.
+type DockerHost struct {
+	Endpoint Endpoint
+
+	mu          sync.Mutex
+	client      Docker
+	err         error
+	connecting  bool
+	lastAttempt time.Time
+}
+
+// Client returns the connection, kicking off a background reconnect if the
+// endpoint is down and it's been a while since the last try.
+func (h *DockerHost) Client() (Docker, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.client != nil {
+		return h.client, nil
+	}
+
+	if !h.connecting && time.Since(h.lastAttempt) >= endpointRetryInterval {
+		h.connecting = true
+		h.lastAttempt = time.Now()
+		go h.connect()
+	}
+
+	if h.connecting {
+		return nil, fmt.Errorf("connecting to %s", h.Endpoint.Host)
+	}
+	return nil, h.err
+}
+
+func (h *DockerHost) connect() {
+	ctx, cancel := context.WithTimeout(rootCtx, endpointConnectTimeout)
+	defer cancel()
+
+	c, err := NewEndpointClient(ctx, h.Endpoint)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.client, h.err, h.connecting = c, err, false
+}
+
+// MarkDown drops the connection after a failed call so the next Client()
+// reports the host as degraded and reconnects.
+func (h *DockerHost) MarkDown(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.client != nil {
+		h.client.Close()
+	}
+	h.client, h.err, h.lastAttempt = nil, err, time.Now()
+}
+
+var (
+	remoteHostsMu sync.Mutex
+	remoteHosts   []*DockerHost
+)
+
+// SetEndpoints registers the extra daemons every screen can reach and starts
+// connecting to them in the background.
+func SetEndpoints(endpoints []Endpoint) {
+	remoteHostsMu.Lock()
+	defer remoteHostsMu.Unlock()
+
+	remoteHosts = nil
+	for _, e := range endpoints {
+		h := &DockerHost{Endpoint: e}
+		h.Client()
+		remoteHosts = append(remoteHosts, h)
+	}
+}
+
+// ConnectEndpoints registers the extra daemons like SetEndpoints, but
+// connects to them before returning, each within the call timeout, for the
+// subcommands that run once and can't wait on a background connect.
+func ConnectEndpoints(endpoints []Endpoint) {
+	hosts := make([]*DockerHost, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		h := &DockerHost{Endpoint: e, lastAttempt: time.Now()}
+		hosts[i] = h
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := callCtx()
+			defer cancel()
+
+			h.client, h.err = NewEndpointClient(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	remoteHostsMu.Lock()
+	defer remoteHostsMu.Unlock()
+
+	remoteHosts = hosts
+}
+
+// RemoteHosts returns the endpoints registered with SetEndpoints.
+func RemoteHosts() []*DockerHost {
+	remoteHostsMu.Lock()
+	defer remoteHostsMu.Unlock()
+
+	return remoteHosts
+}
+
+// RemoteHost looks up a registered endpoint by name.
+func RemoteHost(name string) *DockerHost {
+	for _, h := range RemoteHosts() {
+		if h.Endpoint.Name == name {
+			return h
+		}
+	}
+	return nil
+}
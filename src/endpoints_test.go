@@ -0,0 +1,57 @@
+package src
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// useDockerContexts points the Docker config at an empty directory holding
+// a context per name, for the rest of the test, and returns the directory.
+func useDockerContexts(t *testing.T, hosts map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, host := range hosts {
+		if _, err := dockercontext.New(name, dockercontext.WithHost(host)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestContextEndpoint(t *testing.T) {
+	dir := useDockerContexts(t, map[string]string{
+		"plain":  "tcp://plain.example.com:2376",
+		"secure": "tcp://secure.example.com:2376",
+	})
+	certPath := filepath.Join(dir, "contexts", "tls", fmt.Sprintf("%x", sha256.Sum256([]byte("secure"))), "docker")
+	if err := os.MkdirAll(certPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want Endpoint
+	}{
+		{"plain", Endpoint{Name: "plain", Host: "tcp://plain.example.com:2376"}},
+		{"secure", Endpoint{Name: "secure", Host: "tcp://secure.example.com:2376", CertPath: certPath}},
+	}
+	for _, tt := range tests {
+		got, err := contextEndpoint(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("contextEndpoint(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	dockerClientLib "github.com/docker/docker/client"
+)
+
+const (
+	retryAttempts = 3
+	retryBackoff  = 200 * time.Millisecond
+)
+
+// isTransient reports whether a failed Docker call is worth repeating, as
+// opposed to e.g. a container that no longer exists. A call cut short by its
+// context is final: repeating it under the same context fails the same way.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return dockerClientLib.IsErrConnectionFailed(err) ||
+		cerrdefs.IsUnavailable(err)
+}
+
+// isDaemonUnreachable reports whether err means we lost the daemon itself.
+func isDaemonUnreachable(err error) bool {
+	return dockerClientLib.IsErrConnectionFailed(err)
+}
+
+// retry runs call until it succeeds, fails with a non-transient error or
+// runs out of attempts, backing off a little more each time. It gives up
+// early when ctx is done.
+func retry(ctx context.Context, call func() error) error {
+	var err error
+	for attempt := range retryAttempts {
+		if err = call(); err == nil || !isTransient(err) {
+			return err
+		}
+		if attempt < retryAttempts-1 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(retryBackoff * time.Duration(attempt+1)):
+			}
+		}
+	}
+	return err
+}
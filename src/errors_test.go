@@ -0,0 +1,38 @@
+package src
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+func TestRetry(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		err   error
+		calls int
+	}{
+		{"transient", context.Background(), cerrdefs.ErrUnavailable, retryAttempts},
+		{"final", context.Background(), cerrdefs.ErrNotFound, 1},
+		{"deadline", context.Background(), context.DeadlineExceeded, 1},
+		{"canceled", canceled, cerrdefs.ErrUnavailable, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retry(tt.ctx, func() error {
+				calls++
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) || calls != tt.calls {
+				t.Errorf("retry = %v after %d calls, want %v after %d", err, calls, tt.err, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// A container that stopped gets a "why did it stop" screen: its exit code
+// and what it usually means, whether the kernel killed it for memory, the
+// error the daemon recorded, when it finished and how often it had been
+// restarted, over the last lines it logged.
+
+const (
+	// exitLogLines is how many log lines the exit screen shows, when they
+	// fit
+	exitLogLines = 30
+	// exitChrome is the height of everything around the log lines
+	exitChrome = 16
+	// exitBoxChrome is the width of the margin, border and padding of the
+	// box around them
+	exitBoxChrome = 7
+)
+
+// signals are the ones containers commonly die of, by number.
+var signals = map[int]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	6:  "SIGABRT",
+	9:  "SIGKILL",
+	11: "SIGSEGV",
+	13: "SIGPIPE",
+	15: "SIGTERM",
+}
+
+// exitMeaning explains an exit code the way it is usually meant: codes
+// above 128 are a signal, the others come from the shell or the program.
+func exitMeaning(code int, oomKilled bool) string {
+	switch {
+	case oomKilled:
+		return "killed by the kernel for running out of memory"
+	case code == 0:
+		return "finished successfully"
+	case code == 1:
+		return "the program reported an error"
+	case code == 125:
+		return "the container failed to run"
+	case code == 126:
+		return "the command could not be invoked"
+	case code == 127:
+		return "the command was not found"
+	case code == 137:
+		return "killed with SIGKILL, by docker kill, a stop that timed out or the OOM killer"
+	case code == 143:
+		return "terminated with SIGTERM, usually by docker stop"
+	case code > 128 && code <= 128+64:
+		if name, ok := signals[code-128]; ok {
+			return "killed by " + name
+		}
+		return fmt.Sprintf("killed by signal %d", code-128)
+	}
+	return "the program failed"
+}
+
+// exitDiagnosis is what the exit screen is made of. logsErr says why the
+// logs are missing, when they are.
+type exitDiagnosis struct {
+	inspected containerTypes.InspectResponse
+	logs      string
+	logsErr   error
+}
+
+// DiagnoseExit gathers why containerID stopped. Only failing to inspect it
+// is an error: logs that can't be read, as with a logging driver that
+// keeps none, still leave the exit code and the rest to show.
+func DiagnoseExit(ctx context.Context, dockerClient Docker, containerID string) (exitDiagnosis, error) {
+	inspected, err := InspectContainer(ctx, dockerClient, containerID)
+	if err != nil {
+		return exitDiagnosis{}, err
+	}
+	logs, err := readInspectedLogs(ctx, dockerClient, inspected, containerTypes.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(exitLogLines),
+	})
+	return exitDiagnosis{inspected: inspected, logs: logs, logsErr: err}, nil
+}
+
+// stopped tells whether c is a container that isn't running any more.
+func stopped(c Container) bool {
+	return c.Type == TypeContainer && (c.State == containerTypes.StateExited || c.State == containerTypes.StateDead)
+}
+
+// Exit Model
+
+type exitKeys struct {
+	globalKeys
+	Logs key.Binding
+}
+
+func defaultExitKeys() exitKeys {
+	return exitKeys{
+		globalKeys: defaultGlobalKeys(),
+		Logs: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "all logs"),
+		),
+	}
+}
+
+func newExitKeys() exitKeys {
+	k := defaultExitKeys()
+	remap("global", &k.globalKeys)
+	remap("exit", &k)
+	return k
+}
+
+func (k *exitKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"logs": &k.Logs,
+	}
+}
+
+func (k exitKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Logs, k.Back, k.Help}
+}
+
+func (k exitKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Logs}}, k.globalKeys.FullHelp()...)
+}
+
+type exitModel struct {
+	keys         exitKeys
+	dockerClient Docker
+	viewID       int64
+	container    Container
+	diagnosis    exitDiagnosis
+	width        int
+	height       int
+	loading      bool
+	status       statusBar
+}
+
+func InitExitModel(dockerClient Docker, c Container, width int, height int) exitModel {
+	e := exitModel{
+		keys:         newExitKeys(),
+		dockerClient: dockerClient,
+		viewID:       nextViewID(),
+		container:    c,
+		width:        width,
+		height:       height,
+		status:       newStatusBar(),
+	}
+	e.loading = true
+	e.status.StartLoading()
+
+	return e
+}
+
+type exitMsg struct {
+	viewID    int64
+	diagnosis exitDiagnosis
+	err       error
+}
+
+func (e exitModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Exit "+e.container.Name), e.status.Tick(), e.fetchCmd())
+}
+
+func (e exitModel) client() Docker {
+	return e.dockerClient
+}
+
+func (e exitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		e.keys = newExitKeys()
+		return e, nil
+
+	case tea.WindowSizeMsg:
+		e.width = msg.Width
+		e.height = msg.Height
+		return e, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, e.keys.Help):
+			return e, showHelp("exit "+e.container.Name, e.keys)
+		case key.Matches(msg, e.keys.Back):
+			return e, pop()
+		case key.Matches(msg, e.keys.Quit):
+			return e, tea.Quit
+		case key.Matches(msg, e.keys.Logs):
+			dockerClient, err := hostClient(e.dockerClient, e.container.Host)
+			if err != nil {
+				e.status.Error(err)
+				return e, nil
+			}
+			return e, push(InitLogsModel(dockerClient, e.container.ID, e.container.Name))
+		}
+
+	case exitMsg:
+		if msg.viewID != e.viewID {
+			return e, nil
+		}
+		e.loading = false
+		e.status.StopLoading()
+		if msg.err != nil {
+			e.status.Error(msg.err)
+			return e, nil
+		}
+		e.diagnosis = msg.diagnosis
+		return e, nil
+
+	case spinner.TickMsg:
+		e.status, cmd = e.status.Update(msg)
+		return e, cmd
+	}
+
+	return e, nil
+}
+
+func (e exitModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, c := e.viewID, e.dockerClient, e.container
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		client, err := hostClient(dockerClient, c.Host)
+		if err != nil {
+			return exitMsg{viewID: viewID, err: err}
+		}
+		diagnosis, err := DiagnoseExit(ctx, client, c.ID)
+		return exitMsg{viewID: viewID, diagnosis: diagnosis, err: err}
+	}
+}
+
+func (e exitModel) detailsView() string {
+	inspected := e.diagnosis.inspected
+	if inspected.ContainerJSONBase == nil || inspected.State == nil {
+		return ""
+	}
+	state := inspected.State
+
+	oomKilled := "no"
+	if state.OOMKilled {
+		oomKilled = "yes"
+	}
+	reason := state.Error
+	if reason == "" {
+		reason = "none"
+	}
+	finished := "never"
+	if t, err := time.Parse(time.RFC3339Nano, state.FinishedAt); err == nil && !t.IsZero() {
+		finished = t.Format(time.DateTime)
+	}
+
+	code := fmt.Sprintf("%d, %s", state.ExitCode, exitMeaning(state.ExitCode, state.OOMKilled))
+	if state.ExitCode != 0 || state.OOMKilled {
+		code = StatusErrorStyle.UnsetMarginLeft().Render(code)
+	}
+	return fmt.Sprintf(
+		"Exit code:  %s\nOOM killed: %s\nError:      %s\nFinished:   %s\nRestarts:   %d",
+		code, oomKilled, reason, finished, inspected.RestartCount,
+	)
+}
+
+func (e exitModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(e.width, lipgloss.Left, ContainerTitleStyle.Render("EXIT "+e.container.Name))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	if details := e.detailsView(); details != "" {
+		doc.WriteString(HelpStyle.Render(details) + "\n\n")
+
+		// The lines that fit, cut to the width of the box
+		lines := strings.Split(strings.TrimRight(e.diagnosis.logs, "\n"), "\n")
+		lines = lines[max(0, len(lines)-max(1, min(exitLogLines, e.height-exitChrome))):]
+		heading := fmt.Sprintf("Last %d log lines", len(lines))
+		switch {
+		case e.diagnosis.logsErr != nil:
+			heading, lines = "Logs", []string{"(could not read the logs: " + e.diagnosis.logsErr.Error() + ")"}
+		case len(lines) == 1 && lines[0] == "":
+			heading, lines = "Logs", []string{"(nothing logged)"}
+		}
+		line := lipgloss.NewStyle().MaxWidth(max(0, e.width-exitBoxChrome))
+		for i := range lines {
+			lines[i] = line.Render(lines[i])
+		}
+		doc.WriteString(HelpStyle.Render(heading) + "\n")
+		doc.WriteString(DialogStyle.MaxWidth(max(0, e.width-1)).Render(strings.Join(lines, "\n")) + "\n")
+	}
+
+	if status := e.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(e.keys))
+
+	return doc.String()
+}
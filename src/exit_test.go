@@ -0,0 +1,26 @@
+package src
+
+import "testing"
+
+func TestExitMeaning(t *testing.T) {
+	tests := []struct {
+		code      int
+		oomKilled bool
+		want      string
+	}{
+		{0, false, "finished successfully"},
+		{1, false, "the program reported an error"},
+		{127, false, "the command was not found"},
+		{137, false, "killed with SIGKILL, by docker kill, a stop that timed out or the OOM killer"},
+		{137, true, "killed by the kernel for running out of memory"},
+		{139, false, "killed by SIGSEGV"},
+		{143, false, "terminated with SIGTERM, usually by docker stop"},
+		{158, false, "killed by signal 30"},
+		{255, false, "the program failed"},
+	}
+	for _, tt := range tests {
+		if got := exitMeaning(tt.code, tt.oomKilled); got != tt.want {
+			t.Errorf("exitMeaning(%d, %v) = %q, want %q", tt.code, tt.oomKilled, got, tt.want)
+		}
+	}
+}
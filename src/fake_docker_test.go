@@ -0,0 +1,581 @@
+package src
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// fakeDocker is an in-memory daemon. Containers can be started and stopped,
+// logs and images are canned, and every subscriber to Events gets the
+// recorded events replayed. Like the daemon, it sends the logs of a
+// container without a TTY in stdout frames.
+type fakeDocker struct {
+	mu         sync.Mutex
+	host       string
+	containers []container.Summary
+	logs       map[string]string
+	logErrs    map[string]error
+	tty        map[string]bool
+	stats      map[string]container.StatsResponse
+	health     map[string]*container.Health
+	exits      map[string]container.State
+	restarts   map[string]int
+	top        map[string]container.TopResponse
+	// execs are the commands run in containers, answering with execExit
+	execs    []fakeExec
+	execExit int
+	images   []image.Summary
+	events   []events.Message
+	networks []network.Inspect
+	// networkInspects counts the calls to NetworkInspect
+	networkInspects int
+	volumes         []*volume.Volume
+	closed          bool
+}
+
+var _ Docker = (*fakeDocker)(nil)
+
+func newFakeDocker() *fakeDocker {
+	return &fakeDocker{
+		host:     "unix:///var/run/docker.sock",
+		logs:     map[string]string{},
+		logErrs:  map[string]error{},
+		tty:      map[string]bool{},
+		stats:    map[string]container.StatsResponse{},
+		health:   map[string]*container.Health{},
+		exits:    map[string]container.State{},
+		restarts: map[string]int{},
+		top:      map[string]container.TopResponse{},
+	}
+}
+
+// addContainer registers a container. A non-empty project puts it in that
+// compose project.
+func (f *fakeDocker) addContainer(id string, name string, image string, state container.ContainerState, status string, project string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	labels := map[string]string{}
+	if project != "" {
+		labels[composeStackIdentifier] = project
+	}
+
+	f.containers = append(f.containers, container.Summary{
+		ID:      id,
+		Names:   []string{"/" + name},
+		Image:   image,
+		Labels:  labels,
+		State:   state,
+		Status:  status,
+		Created: time.Date(2025, 1, 1, len(f.containers), 0, 0, 0, time.UTC).Unix(),
+	})
+}
+
+func (f *fakeDocker) setPorts(id string, ports ...container.Port) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.containers {
+		if f.containers[i].ID == id {
+			f.containers[i].Ports = ports
+		}
+	}
+}
+
+func (f *fakeDocker) setLogs(id string, logs string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.logs[id] = logs
+}
+
+// failLogs makes reading the logs of the container fail with err, as with
+// a logging driver that keeps none.
+func (f *fakeDocker) failLogs(id string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.logErrs[id] = err
+}
+
+// setTTY gives the container a terminal, so its logs come unframed.
+func (f *fakeDocker) setTTY(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.tty[id] = true
+}
+
+// setStats makes the container use cpu percent of one of two cores and
+// memory bytes.
+func (f *fakeDocker) setStats(id string, cpu float64, memory uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stats container.StatsResponse
+	stats.CPUStats.OnlineCPUs = 2
+	stats.CPUStats.SystemUsage = 2_000_000
+	stats.PreCPUStats.SystemUsage = 1_000_000
+	stats.CPUStats.CPUUsage.TotalUsage = uint64(cpu * 1_000_000 / 200)
+	stats.MemoryStats.Usage = memory
+	f.stats[id] = stats
+}
+
+// setHealth gives the container a healthcheck that went as health says,
+// and the status to go with it.
+func (f *fakeDocker) setHealth(id string, health *container.Health) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.health[id] = health
+	for i := range f.containers {
+		if f.containers[i].ID == id {
+			status := "(" + health.Status + ")"
+			if health.Status == container.Starting {
+				status = "(health: starting)"
+			}
+			f.containers[i].Status += " " + status
+		}
+	}
+}
+
+// setExit records how the container last exited and how often it was
+// restarted, for inspecting.
+func (f *fakeDocker) setExit(id string, exit container.State, restarts int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.exits[id] = exit
+	f.restarts[id] = restarts
+}
+
+// setTop sets what docker top lists for the container.
+func (f *fakeDocker) setTop(id string, titles []string, processes ...[]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.top[id] = container.TopResponse{Titles: titles, Processes: processes}
+}
+
+// fakeExec is a command run in a container.
+type fakeExec struct {
+	containerID string
+	cmd         []string
+}
+
+func (f *fakeDocker) ran() []fakeExec {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.execs)
+}
+
+func (f *fakeDocker) addImage(id string, tags ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.images = append(f.images, image.Summary{
+		ID:       id,
+		RepoTags: tags,
+		Created:  time.Now().Add(-48 * time.Hour).Unix(),
+		Size:     190 * 1000 * 1000,
+	})
+}
+
+func (f *fakeDocker) addVolume(name string, driver string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.volumes = append(f.volumes, &volume.Volume{
+		Name:       name,
+		Driver:     driver,
+		Scope:      "local",
+		Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+		CreatedAt:  "2025-01-01T10:00:00Z",
+	})
+}
+
+func (f *fakeDocker) addEvent(event events.Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, event)
+}
+
+func (f *fakeDocker) container(id string) (*container.Summary, error) {
+	for i := range f.containers {
+		if f.containers[i].ID == id || slices.Contains(f.containers[i].Names, "/"+id) {
+			return &f.containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no such container: %s: %w", id, cerrdefs.ErrNotFound)
+}
+
+func (f *fakeDocker) network(id string) (*network.Inspect, error) {
+	for i := range f.networks {
+		if f.networks[i].ID == id || f.networks[i].Name == id {
+			return &f.networks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("network %s not found: %w", id, cerrdefs.ErrNotFound)
+}
+
+func (f *fakeDocker) DaemonHost() string {
+	return f.host
+}
+
+func (f *fakeDocker) Ping(ctx context.Context) (types.Ping, error) {
+	return types.Ping{APIVersion: "1.51", OSType: "linux"}, nil
+}
+
+func (f *fakeDocker) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+// isClosed tells whether the client was closed.
+func (f *fakeDocker) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+func (f *fakeDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	containers := []container.Summary{}
+	for _, c := range f.containers {
+		if options.All || c.State == container.StateRunning {
+			c.NetworkSettings = f.networkSettings(c.ID)
+			containers = append(containers, c)
+		}
+	}
+	return containers, nil
+}
+
+// networkSettings lists the networks containerID is connected to, the way
+// the container list reports them.
+func (f *fakeDocker) networkSettings(containerID string) *container.NetworkSettingsSummary {
+	settings := &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{}}
+	for _, n := range f.networks {
+		if endpoint, ok := n.Containers[containerID]; ok {
+			ip, prefix, _ := strings.Cut(endpoint.IPv4Address, "/")
+			prefixLen, _ := strconv.Atoi(prefix)
+			settings.Networks[n.Name] = &network.EndpointSettings{
+				NetworkID:   n.ID,
+				EndpointID:  endpoint.EndpointID,
+				MacAddress:  endpoint.MacAddress,
+				IPAddress:   ip,
+				IPPrefixLen: prefixLen,
+			}
+		}
+	}
+	return settings
+}
+
+func (f *fakeDocker) ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.logErrs[c.ID]; err != nil {
+		return nil, err
+	}
+	logs := f.logs[c.ID]
+	if n, err := strconv.Atoi(options.Tail); err == nil {
+		lines := strings.SplitAfter(logs, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		logs = strings.Join(lines[max(0, len(lines)-n):], "")
+	}
+	if f.tty[c.ID] {
+		return io.NopCloser(strings.NewReader(logs)), nil
+	}
+	framed := bytes.Buffer{}
+	if _, err := stdcopy.NewStdWriter(&framed, stdcopy.Stdout).Write([]byte(logs)); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&framed), nil
+}
+
+func (f *fakeDocker) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.container(containerID); err != nil {
+		return container.StatsResponseReader{}, err
+	}
+	data, err := json.Marshal(f.stats[containerID])
+	if err != nil {
+		return container.StatsResponseReader{}, err
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (f *fakeDocker) ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+	config := &container.Config{Image: c.Image, Labels: c.Labels, Tty: f.tty[c.ID]}
+	if f.health[c.ID] != nil {
+		config.Healthcheck = &container.HealthConfig{Test: []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}, Interval: 30 * time.Second}
+	}
+	state := f.exits[c.ID]
+	state.Status = c.State
+	state.Running = c.State == container.StateRunning
+	state.Paused = c.State == container.StatePaused
+	state.Health = f.health[c.ID]
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:           c.ID,
+			Name:         c.Names[0],
+			Image:        c.ImageID,
+			Created:      time.Unix(c.Created, 0).UTC().Format(time.RFC3339Nano),
+			State:        &state,
+			RestartCount: f.restarts[c.ID],
+		},
+		Config: config,
+	}, nil
+}
+
+func (f *fakeDocker) ContainerTop(ctx context.Context, containerID string, arguments []string) (container.TopResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return container.TopResponse{}, err
+	}
+	if c.State != container.StateRunning {
+		return container.TopResponse{}, fmt.Errorf("container %s is not running: %w", c.ID, cerrdefs.ErrConflict)
+	}
+	return f.top[c.ID], nil
+}
+
+func (f *fakeDocker) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return container.ExecCreateResponse{}, err
+	}
+	f.execs = append(f.execs, fakeExec{containerID: c.ID, cmd: options.Cmd})
+	return container.ExecCreateResponse{ID: fmt.Sprintf("exec-%d", len(f.execs))}, nil
+}
+
+func (f *fakeDocker) ContainerExecStart(ctx context.Context, execID string, options container.ExecStartOptions) error {
+	return nil
+}
+
+func (f *fakeDocker) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return container.ExecInspect{ExecID: execID, ExitCode: f.execExit}, nil
+}
+
+func (f *fakeDocker) ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	c.State, c.Status = container.StateRunning, "Up Less than a second"
+	return nil
+}
+
+func (f *fakeDocker) ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	c.State, c.Status = container.StateExited, "Exited (0) Less than a second ago"
+	return nil
+}
+
+func (f *fakeDocker) ContainerRestart(ctx context.Context, containerID string, options container.StopOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	c.State, c.Status = container.StateRunning, "Up Less than a second"
+	return nil
+}
+
+func (f *fakeDocker) ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	if c.State == container.StateRunning && !options.Force {
+		return fmt.Errorf("cannot remove container %q: container is running: stop the container before removing or force remove: %w", c.Names[0], cerrdefs.ErrConflict)
+	}
+	f.containers = slices.DeleteFunc(f.containers, func(s container.Summary) bool { return s.ID == c.ID })
+	return nil
+}
+
+func (f *fakeDocker) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.images), nil
+}
+
+func (f *fakeDocker) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := make(chan events.Message, len(f.events))
+	for _, event := range f.events {
+		messages <- event
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		errs <- ctx.Err()
+	}()
+
+	return messages, errs
+}
+
+func (f *fakeDocker) NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Like the daemon, the list leaves out the attached containers
+	networks := []network.Summary{}
+	for _, n := range f.networks {
+		n.Containers = nil
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+func (f *fakeDocker) NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.networkInspects++
+	n, err := f.network(networkID)
+	if err != nil {
+		return network.Inspect{}, err
+	}
+	return *n, nil
+}
+
+func (f *fakeDocker) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := fmt.Sprintf("%064d", len(f.networks)+1)
+	f.networks = append(f.networks, network.Inspect{
+		ID:         id,
+		Name:       name,
+		Driver:     options.Driver,
+		Scope:      "local",
+		Labels:     options.Labels,
+		Containers: map[string]network.EndpointResource{},
+	})
+	return network.CreateResponse{ID: id}, nil
+}
+
+func (f *fakeDocker) NetworkRemove(ctx context.Context, networkID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.network(networkID)
+	if err != nil {
+		return err
+	}
+	id := n.ID
+	f.networks = slices.DeleteFunc(f.networks, func(n network.Inspect) bool { return n.ID == id })
+	return nil
+}
+
+func (f *fakeDocker) NetworkConnect(ctx context.Context, networkID string, containerID string, config *network.EndpointSettings) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.network(networkID)
+	if err != nil {
+		return err
+	}
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	if n.Containers == nil {
+		n.Containers = map[string]network.EndpointResource{}
+	}
+	n.Containers[c.ID] = network.EndpointResource{
+		Name:        strings.TrimLeft(c.Names[0], "/"),
+		IPv4Address: fmt.Sprintf("172.18.0.%d/16", len(n.Containers)+2),
+	}
+	return nil
+}
+
+func (f *fakeDocker) NetworkDisconnect(ctx context.Context, networkID string, containerID string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.network(networkID)
+	if err != nil {
+		return err
+	}
+	c, err := f.container(containerID)
+	if err != nil {
+		return err
+	}
+	delete(n.Containers, c.ID)
+	return nil
+}
+
+func (f *fakeDocker) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return volume.ListResponse{Volumes: slices.Clone(f.volumes)}, nil
+}
@@ -0,0 +1,154 @@
+package src
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// containerFilter narrows the container list down to the containers every
+// term matches. Plain words are matched fuzzily, the letters in order, against
+// the name, image, ID and compose project; other terms are written key=value:
+//
+//	label=com.example.team        has the label
+//	label=com.example.team=web    has the label with that value
+//	state=running                 is in that state
+//	health=unhealthy              has a healthcheck in that state, or none
+//	image=nginx                   runs an image whose name contains nginx
+//	name=api                      has a name containing api
+//
+// A compose stack whose name matches keeps all its services; otherwise it
+// stays when one of its services matches, with only those under it.
+type containerFilter struct {
+	query string
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	key   string
+	value string
+}
+
+var filterKeys = []string{"label", "state", "health", "image", "name"}
+
+func parseContainerFilter(query string) (containerFilter, error) {
+	f := containerFilter{query: strings.TrimSpace(query)}
+	for _, field := range strings.Fields(query) {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			f.terms = append(f.terms, filterTerm{value: field})
+			continue
+		}
+		if v == "" {
+			return containerFilter{}, fmt.Errorf("filter: %q needs a value", field)
+		}
+		if !slices.Contains(filterKeys, k) {
+			return containerFilter{}, fmt.Errorf("filter: unknown key %q, use one of %v", k, filterKeys)
+		}
+		f.terms = append(f.terms, filterTerm{key: k, value: v})
+	}
+	return f, nil
+}
+
+func (f containerFilter) empty() bool {
+	return len(f.terms) == 0
+}
+
+func (f containerFilter) matches(c Container) bool {
+	for _, t := range f.terms {
+		if !t.matches(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t filterTerm) matches(c Container) bool {
+	switch t.key {
+	case "":
+		project := c.Labels[composeStackIdentifier]
+		if c.Type == TypeComposeStack {
+			project = c.Name
+		}
+		for _, field := range []string{c.Name, c.Image, c.ID, project} {
+			if fuzzyMatch(t.value, field) {
+				return true
+			}
+		}
+		return false
+	case "label":
+		name, value, hasValue := strings.Cut(t.value, "=")
+		got, ok := c.Labels[name]
+		return ok && (!hasValue || got == value)
+	case "state":
+		return strings.EqualFold(string(c.State), t.value)
+	case "health":
+		return c.Type != TypeComposeStack && strings.EqualFold(containerHealth(c), t.value)
+	case "image":
+		return strings.Contains(strings.ToLower(c.Image), strings.ToLower(t.value))
+	case "name":
+		return strings.Contains(strings.ToLower(c.Name), strings.ToLower(t.value))
+	}
+	return false
+}
+
+// apply keeps the containers f matches, and the stacks with services it
+// matches.
+func (f containerFilter) apply(containers []Container) []Container {
+	if f.empty() {
+		return containers
+	}
+
+	kept := []Container{}
+	for _, c := range containers {
+		if f.matches(c) {
+			kept = append(kept, c)
+			continue
+		}
+		if c.Type != TypeComposeStack {
+			continue
+		}
+
+		children := []Container{}
+		for _, child := range c.Children {
+			if f.matches(child) {
+				children = append(children, child)
+			}
+		}
+		if len(children) > 0 {
+			c.Children = children
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// fuzzyMatch reports whether the letters of pattern appear in s in order,
+// ignoring case.
+func fuzzyMatch(pattern string, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// countContainers counts the containers in list, services of stacks
+// included.
+func countContainers(list []Container) int {
+	n := 0
+	for _, c := range list {
+		switch c.Type {
+		case TypeContainer:
+			n++
+		case TypeComposeStack:
+			n += len(c.Children)
+		}
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package src
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestContainerFilter(t *testing.T) {
+	containers := []Container{
+		{ID: "6f1c2b7d9e0a", Name: "nginx", Type: TypeContainer, Image: "nginx:1.27", State: container.StateRunning, Labels: map[string]string{"team": "web"}},
+		{Name: "worker", Type: TypeContainer, Image: "busybox:latest", State: container.StateExited},
+		{Name: "shop", Type: TypeComposeStack, Children: []Container{
+			{Name: "shop-api", Type: TypeContainer, Image: "shop/api:dev", State: container.StateRunning, Labels: map[string]string{"team": "shop", composeStackIdentifier: "shop"}},
+			{Name: "shop-db", Type: TypeContainer, Image: "postgres:16", State: container.StateExited, Labels: map[string]string{composeStackIdentifier: "shop"}},
+		}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"nginx", "worker", "shop"}},
+		{"state=running", []string{"nginx", "shop", "shop-api"}},
+		{"label=team", []string{"nginx", "shop", "shop-api"}},
+		{"label=team=web", []string{"nginx"}},
+		{"image=POSTGRES", []string{"shop", "shop-db"}},
+		{"state=exited name=work", []string{"worker"}},
+		{"ngx", []string{"nginx"}},
+		{"6f1c", []string{"nginx"}},
+		{"busy", []string{"worker"}},
+		{"shp", []string{"shop", "shop-api", "shop-db"}},
+		{"shp state=exited", []string{"shop", "shop-db"}},
+		{"pgs", []string{"shop", "shop-db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			f, err := parseContainerFilter(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, c := range f.apply(containers) {
+				got = append(got, c.Name)
+				if tt.query == "" {
+					continue
+				}
+				for _, child := range c.Children {
+					got = append(got, child.Name)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("filter %q kept %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	for _, query := range []string{"color=red", "label="} {
+		if _, err := parseContainerFilter(query); err == nil {
+			t.Errorf("filter %q parsed without an error", query)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// Containers with a healthcheck say how it goes at the end of their status,
+// "Up 3 hours (healthy)", which is where the Health column reads it from. A
+// stack is as healthy as its sickest service. The probes themselves only
+// come with an inspect, done by the health screen.
+
+// healthOrder puts the containers needing attention first.
+var healthOrder = []containerTypes.HealthStatus{
+	containerTypes.Unhealthy,
+	containerTypes.Starting,
+	containerTypes.Healthy,
+	containerTypes.NoHealthcheck,
+}
+
+// containerHealth is the health of c, NoHealthcheck when it has none.
+func containerHealth(c Container) containerTypes.HealthStatus {
+	if c.Type == TypeComposeStack {
+		return healthOrder[healthRank(c)]
+	}
+	switch {
+	case strings.HasSuffix(c.Status, "(unhealthy)"):
+		return containerTypes.Unhealthy
+	case strings.HasSuffix(c.Status, "(health: starting)"):
+		return containerTypes.Starting
+	case strings.HasSuffix(c.Status, "(healthy)"):
+		return containerTypes.Healthy
+	}
+	return containerTypes.NoHealthcheck
+}
+
+func healthRank(c Container) int {
+	if c.Type == TypeComposeStack {
+		rank := len(healthOrder) - 1
+		for _, child := range c.Children {
+			rank = min(rank, healthRank(child))
+		}
+		return rank
+	}
+	return slices.Index(healthOrder, containerHealth(c))
+}
+
+// containerHealthColumn leaves the health of containers without a
+// healthcheck blank.
+func containerHealthColumn(c Container) string {
+	if health := containerHealth(c); health != containerTypes.NoHealthcheck {
+		return health
+	}
+	return ""
+}
+
+func InspectContainer(ctx context.Context, dockerClient Docker, containerID string) (containerTypes.InspectResponse, error) {
+	var inspected containerTypes.InspectResponse
+	err := retry(ctx, func() (err error) {
+		inspected, err = dockerClient.ContainerInspect(ctx, containerID)
+		return err
+	})
+	return inspected, err
+}
+
+// Health Model
+
+// healthModel lists the latest healthcheck probes of a container, newest
+// first.
+type healthModel struct {
+	keys         scrollKeys
+	dockerClient Docker
+	viewID       int64
+	container    Container
+	inspected    containerTypes.InspectResponse
+	width        int
+	height       int
+	table        table.Model
+	loading      bool
+	status       statusBar
+}
+
+// healthChrome is the height of everything around the probes table: the
+// chrome of the containers table and the details above it.
+const healthChrome = containersChrome + 5
+
+func InitHealthModel(dockerClient Docker, c Container, width int, height int) healthModel {
+	keys := newScrollKeys("health")
+	t := table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(keys.tableKeyMap()),
+	)
+	t.SetStyles(tableStyles())
+
+	h := healthModel{
+		keys:         keys,
+		dockerClient: dockerClient,
+		viewID:       nextViewID(),
+		container:    c,
+		width:        width,
+		height:       height,
+		table:        t,
+		status:       newStatusBar(),
+	}
+	h.resize()
+	h.loading = true
+	h.status.StartLoading()
+
+	return h
+}
+
+type healthMsg struct {
+	viewID    int64
+	inspected containerTypes.InspectResponse
+	err       error
+}
+
+func (h healthModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Health "+h.container.Name), h.status.Tick(), h.fetchCmd(), tickCmd(h.viewID))
+}
+
+func (h healthModel) client() Docker {
+	return h.dockerClient
+}
+
+func (h healthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		h.keys = newScrollKeys("health")
+		h.table.KeyMap = h.keys.tableKeyMap()
+		h.table.SetStyles(tableStyles())
+		return h, nil
+
+	case tea.WindowSizeMsg:
+		h.width = msg.Width
+		h.height = msg.Height
+		h.resize()
+		h.setProbes()
+		return h, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, h.keys.Help):
+			return h, showHelp("health "+h.container.Name, h.keys)
+		case key.Matches(msg, h.keys.Back):
+			return h, pop()
+		case key.Matches(msg, h.keys.Quit):
+			return h, tea.Quit
+		}
+
+	case healthMsg:
+		if msg.viewID != h.viewID {
+			return h, nil
+		}
+		h.loading = false
+		h.status.StopLoading()
+		if msg.err != nil {
+			// Keep the last probes, the container may have been removed
+			// underneath us
+			h.status.Error(msg.err)
+			return h, nil
+		}
+		h.inspected = msg.inspected
+		h.setProbes()
+		return h, nil
+
+	case pingMsg:
+		if msg.viewID != h.viewID || msg.err != nil {
+			return h, nil
+		}
+		h.status.Recovered()
+		cmd = h.refresh()
+		return h, cmd
+
+	case spinner.TickMsg:
+		h.status, cmd = h.status.Update(msg)
+		return h, cmd
+
+	case tickMsg:
+		if msg.viewID != h.viewID {
+			return h, nil
+		}
+		cmd = h.refresh()
+		return h, tea.Batch(cmd, tickCmd(h.viewID))
+	}
+
+	h.table, cmd = h.table.Update(msg)
+
+	return h, cmd
+}
+
+// refresh re-inspects the container unless a request is already on its
+// way. While the daemon is unreachable it only pings it.
+func (h *healthModel) refresh() tea.Cmd {
+	if h.status.Unreachable() {
+		return pingCmd(h.viewID, h.dockerClient)
+	}
+	if h.loading {
+		return nil
+	}
+	h.loading = true
+	return h.fetchCmd()
+}
+
+func (h healthModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, c := h.viewID, h.dockerClient, h.container
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		client, err := hostClient(dockerClient, c.Host)
+		if err != nil {
+			return healthMsg{viewID: viewID, err: err}
+		}
+		inspected, err := InspectContainer(ctx, client, c.ID)
+		return healthMsg{viewID: viewID, inspected: inspected, err: err}
+	}
+}
+
+// resize gives the output of the probes what the other columns leave.
+func (h *healthModel) resize() {
+	columns := []table.Column{
+		{Title: "Started", Width: 19},
+		{Title: "Took", Width: 8},
+		{Title: "Exit", Width: 4},
+		{Title: "Output", Width: 0},
+	}
+	used := tableChrome
+	for _, c := range columns[:len(columns)-1] {
+		used += c.Width + cellPadding
+	}
+	columns[len(columns)-1].Width = max(minFlexWidth, h.width-used-cellPadding)
+
+	h.table.SetRows(nil)
+	h.table.SetColumns(columns)
+	h.table.SetHeight(max(1, h.height-healthChrome))
+}
+
+func (h *healthModel) setProbes() {
+	rows := []table.Row{}
+	if health := h.health(); health != nil {
+		for _, probe := range slices.Backward(health.Log) {
+			rows = append(rows, table.Row{
+				probe.Start.Format(time.DateTime),
+				probe.End.Sub(probe.Start).Round(time.Millisecond).String(),
+				fmt.Sprint(probe.ExitCode),
+				strings.Join(strings.Fields(probe.Output), " "),
+			})
+		}
+	}
+	h.table.SetRows(rows)
+}
+
+func (h healthModel) health() *containerTypes.Health {
+	if h.inspected.ContainerJSONBase == nil || h.inspected.State == nil {
+		return nil
+	}
+	return h.inspected.State.Health
+}
+
+func (h healthModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(h.width, lipgloss.Left, ContainerTitleStyle.Render("HEALTH "+h.container.Name))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	state, streak, test, interval := containerTypes.NoHealthcheck, "0", "none", "default"
+	if health := h.health(); health != nil {
+		state, streak = health.Status, fmt.Sprint(health.FailingStreak)
+	}
+	if h.inspected.Config != nil && h.inspected.Config.Healthcheck != nil {
+		check := h.inspected.Config.Healthcheck
+		if len(check.Test) > 0 {
+			test = strings.Join(check.Test, " ")
+		}
+		if check.Interval > 0 {
+			interval = check.Interval.String()
+		}
+	}
+	details := fmt.Sprintf(
+		"Status:         %s\nFailing streak: %s\nCheck:          %s\nInterval:       %s",
+		state, streak, test, interval,
+	)
+	doc.WriteString(HelpStyle.Render(details) + "\n\n")
+
+	doc.WriteString(TableStyle.Render(h.table.View()) + "\n")
+
+	if status := h.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(h.keys))
+
+	return doc.String()
+}
@@ -1,51 +1,349 @@
 package src
 
-import "github.com/charmbracelet/bubbles/key"
-
-// keyMap defines a set of keybindings. To work for help it must satisfy
-// key.Map. It could also very easily be a map[string]key.Binding.
-type keyMap struct {
-	Up    key.Binding
-	Down  key.Binding
-	Left  key.Binding
-	Right key.Binding
-	Help  key.Binding
-}
-
-var keys = keyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "move down"),
-	),
-	Left: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "back"),
-	),
-	Right: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "forward"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Every screen owns a key map listing its actions, next to the globalKeys
+// all screens share. Key maps name their actions so the keybindings section
+// of the config can remap them, per screen or under "global":
+//
+//	keybindings:
+//	  global:
+//	    back: [esc, h]
+//	  containers:
+//	    start_stop: [s]
+//
+// They also satisfy key.Map, which feeds the one-line help under each
+// screen and the full-screen overlay behind the help key.
+
+// namedKeys is a key map whose bindings can be looked up by action name.
+type namedKeys interface {
+	named() map[string]*key.Binding
+}
+
+// keyMaps makes the default key map of every screen, by the name the
+// config uses for it.
+var keyMaps = map[string]func() namedKeys{
+	"global":     func() namedKeys { k := defaultGlobalKeys(); return &k },
+	"index":      func() namedKeys { k := defaultIndexKeys(); return &k },
+	"containers": func() namedKeys { k := defaultContainersKeys(); return &k },
+	"networks":   func() namedKeys { k := defaultNetworksKeys(); return &k },
+	"network":    func() namedKeys { k := defaultNetworkKeys(); return &k },
+	"contexts":   func() namedKeys { k := defaultContextsKeys(); return &k },
+	"processes":  func() namedKeys { k := defaultProcessesKeys(); return &k },
+	"logs":       func() namedKeys { k := defaultScrollKeys(); return &k },
+	"topology":   func() namedKeys { k := defaultScrollKeys(); return &k },
+	"images":     func() namedKeys { k := defaultScrollKeys(); return &k },
+	"volumes":    func() namedKeys { k := defaultScrollKeys(); return &k },
+	"health":     func() namedKeys { k := defaultScrollKeys(); return &k },
+	"crashes":    func() namedKeys { k := defaultScrollKeys(); return &k },
+	"exit":       func() namedKeys { k := defaultExitKeys(); return &k },
+	"summary":    func() namedKeys { k := defaultSummaryKeys(); return &k },
+}
+
+// remap applies the config's keybindings for view to k.
+func remap(view string, k namedKeys) {
+	bound := k.named()
+	for action, keys := range CurrentConfig().Keybindings[view] {
+		if b, ok := bound[action]; ok {
+			b.SetKeys(keys...)
+			b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+		}
+	}
+}
+
+// validateKeybindings checks the keybindings section of c: screens and
+// actions must exist, and no key may do two things on the same screen.
+func validateKeybindings(c Config) []error {
+	errs := []error{}
+
+	for view, actions := range c.Keybindings {
+		newKeys, ok := keyMaps[view]
+		if !ok {
+			errs = append(errs, fmt.Errorf("keybindings: unknown screen %q", view))
+			continue
+		}
+		known := newKeys().named()
+		for action, keys := range actions {
+			if _, ok := known[action]; !ok {
+				errs = append(errs, fmt.Errorf("keybindings.%s: unknown action %q", view, action))
+			}
+			if len(keys) == 0 {
+				errs = append(errs, fmt.Errorf("keybindings.%s.%s: at least one key is needed", view, action))
+			}
+		}
+	}
+
+	// Look for clashes in each screen's keys merged with the global ones
+	for view, newKeys := range keyMaps {
+		if view == "global" {
+			continue
+		}
+		actions := map[string]*key.Binding{}
+		for scope, k := range map[string]namedKeys{"global": keyMaps["global"](), view: newKeys()} {
+			for action, b := range k.named() {
+				if keys, ok := c.Keybindings[scope][action]; ok {
+					b.SetKeys(keys...)
+				}
+				actions[action] = b
+			}
+		}
+		owner := map[string]string{}
+		for _, action := range slices.Sorted(maps.Keys(actions)) {
+			for _, k := range actions[action].Keys() {
+				if other, ok := owner[k]; ok {
+					errs = append(errs, fmt.Errorf("keybindings.%s: %q is bound to both %s and %s", view, k, other, action))
+				}
+				owner[k] = action
+			}
+		}
+	}
+
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errs
+}
+
+// globalKeys are the actions every screen has.
+type globalKeys struct {
+	Up      key.Binding
+	Down    key.Binding
+	Back    key.Binding
+	Help    key.Binding
+	Command key.Binding
+	Quit    key.Binding
+}
+
+func defaultGlobalKeys() globalKeys {
+	return globalKeys{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "move down"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Command: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+func newGlobalKeys() globalKeys {
+	k := defaultGlobalKeys()
+	remap("global", &k)
+	return k
+}
+
+func (k *globalKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":      &k.Up,
+		"down":    &k.Down,
+		"back":    &k.Back,
+		"help":    &k.Help,
+		"command": &k.Command,
+		"quit":    &k.Quit,
+	}
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help}
+func (k globalKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Help}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
 // key.Map interface.
-func (k keyMap) FullHelp() [][]key.Binding {
+func (k globalKeys) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right}, // first column
-		{k.Help},                        // second column
+		{k.Up, k.Down, k.Back, k.Help, k.Command, k.Quit},
+	}
+}
+
+// tableKeyMap moves table cursors with the configured up and down keys.
+func tableKeyMap() table.KeyMap {
+	g := newGlobalKeys()
+
+	km := table.DefaultKeyMap()
+	km.LineUp = g.Up
+	km.LineDown = g.Down
+	return km
+}
+
+// scrollKeys are the keys of a screen that only shows a table or a
+// viewport to scroll through.
+type scrollKeys struct {
+	globalKeys
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+}
+
+func defaultScrollKeys() scrollKeys {
+	return scrollKeys{
+		globalKeys: defaultGlobalKeys(),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("pgup/b", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f"),
+			key.WithHelp("pgdn/f", "page down"),
+		),
+		HalfPageUp: key.NewBinding(
+			key.WithKeys("ctrl+u", "u"),
+			key.WithHelp("ctrl+u/u", "half page up"),
+		),
+		HalfPageDown: key.NewBinding(
+			key.WithKeys("ctrl+d", "d"),
+			key.WithHelp("ctrl+d/d", "half page down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("home/g", "go to top"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("end/G", "go to bottom"),
+		),
 	}
 }
+
+// newScrollKeys makes the keys of view, one of the screens registered with
+// defaultScrollKeys in keyMaps.
+func newScrollKeys(view string) scrollKeys {
+	k := defaultScrollKeys()
+	remap("global", &k.globalKeys)
+	remap(view, &k)
+	return k
+}
+
+func (k *scrollKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"page_up":        &k.PageUp,
+		"page_down":      &k.PageDown,
+		"half_page_up":   &k.HalfPageUp,
+		"half_page_down": &k.HalfPageDown,
+		"top":            &k.Top,
+		"bottom":         &k.Bottom,
+	}
+}
+
+func (k scrollKeys) ShortHelp() []key.Binding {
+	return k.globalKeys.ShortHelp()
+}
+
+func (k scrollKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom}}, k.globalKeys.FullHelp()...)
+}
+
+// tableKeyMap moves a table cursor with k.
+func (k scrollKeys) tableKeyMap() table.KeyMap {
+	km := table.DefaultKeyMap()
+	km.LineUp = k.Up
+	km.LineDown = k.Down
+	km.PageUp = k.PageUp
+	km.PageDown = k.PageDown
+	km.HalfPageUp = k.HalfPageUp
+	km.HalfPageDown = k.HalfPageDown
+	km.GotoTop = k.Top
+	km.GotoBottom = k.Bottom
+	return km
+}
+
+// viewportKeyMap scrolls a viewport with k. A viewport has no keys for its
+// ends, so the screen handles Top and Bottom itself.
+func (k scrollKeys) viewportKeyMap() viewport.KeyMap {
+	km := viewport.DefaultKeyMap()
+	km.Up = k.Up
+	km.Down = k.Down
+	km.PageUp = k.PageUp
+	km.PageDown = k.PageDown
+	km.HalfPageUp = k.HalfPageUp
+	km.HalfPageDown = k.HalfPageDown
+	return km
+}
+
+// mutating turns off bindings that change anything while in read-only
+// mode, so they neither match nor show up in the help.
+func mutating(bindings ...*key.Binding) {
+	if !readOnly() {
+		return
+	}
+	for _, b := range bindings {
+		b.SetEnabled(false)
+	}
+}
+
+// helpLine renders the one-line help under a screen, saying so in
+// read-only mode.
+func helpLine(keys help.KeyMap) string {
+	line := help.New().ShortHelpView(keys.ShortHelp())
+	if readOnly() {
+		line = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Warning)).Render("read-only") + " • " + line
+	}
+	return HelpStyle.Render(line)
+}
+
+// helpMsg asks the router to cover the screen with the keys of title.
+type helpMsg struct {
+	title string
+	keys  help.KeyMap
+}
+
+func showHelp(title string, keys help.KeyMap) tea.Cmd {
+	return func() tea.Msg {
+		return helpMsg{title: title, keys: keys}
+	}
+}
+
+// renderHelp lays out every binding of keys, one group per block.
+func renderHelp(title string, keys help.KeyMap, width int) string {
+	doc := strings.Builder{}
+
+	doc.WriteString(lipgloss.PlaceHorizontal(width, lipgloss.Left, ContainerTitleStyle.Render("KEYS · "+strings.ToUpper(title))))
+	doc.WriteString("\n\n")
+
+	keyStyle := lipgloss.NewStyle().Bold(true).Width(16)
+	for _, group := range keys.FullHelp() {
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			line := keyStyle.Render(strings.Join(b.Keys(), " / ")) + b.Help().Desc
+			doc.WriteString(HelpStyle.Render(line) + "\n")
+		}
+		doc.WriteString("\n")
+	}
+
+	doc.WriteString(HelpStyle.Render("Press any key to close."))
+
+	return doc.String()
+}
@@ -1,103 +1,273 @@
 package src
 
 import (
-	"fmt"
-	"math/rand"
+	"context"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/docker/go-sdk/client"
+	"github.com/charmbracelet/lipgloss"
+	imageTypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/go-units"
 )
 
 // List Images Model
 
 type listImagesModel struct {
-	cursor                    int
-	containers                []string
-	containersDownloadPercent map[int]int
-	help                      help.Model
-	keys                      keyMap
-	dockerClient              client.SDKClient
+	keys         scrollKeys
+	dockerClient Docker
+	viewID       int64
+	width        int
+	height       int
+	table        table.Model
+	loading      bool
+	status       statusBar
 }
 
-func InitListImagesModel(dockerClient client.SDKClient) listImagesModel {
-	list := []string{"Postgres", "Redis", "Kafka", "Star Trek", "Forza Horizon 5"}
-	percent := make(map[int]int)
-	for i := range list {
-		percent[i] = rand.Intn(101)
+// Image is one tag of an image, the way docker images lists them. An image
+// without tags is listed once as <none>.
+type Image struct {
+	ID         string
+	Repository string
+	Tag        string
+	Created    time.Time
+	Size       int64
+}
+
+func init() {
+	registerCommand(command{
+		name: "images",
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			return push(InitListImagesModel(env.dockerClient, env.width, env.height)), nil
+		},
+	})
+}
+
+func InitListImagesModel(dockerClient Docker, width int, height int) listImagesModel {
+	columns := []table.Column{
+		{Title: "Repository", Width: 40},
+		{Title: "Tag", Width: 20},
+		{Title: "Image ID", Width: 12},
+		{Title: "Created", Width: 16},
+		{Title: "Size", Width: 10},
 	}
-	return listImagesModel{
-		containers:                list,
-		containersDownloadPercent: percent,
-		help:                      help.New(),
-		keys:                      keys,
-		dockerClient:              dockerClient,
+
+	keys := newScrollKeys("images")
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithKeyMap(keys.tableKeyMap()),
+		table.WithHeight(height-10),
+	)
+
+	t.SetStyles(tableStyles())
+
+	l := listImagesModel{
+		keys:         keys,
+		dockerClient: dockerClient,
+		width:        width,
+		height:       height,
+		table:        t,
+		viewID:       nextViewID(),
+		status:       newStatusBar(),
 	}
+	l.loading = true
+	l.status.StartLoading()
+
+	return l
+}
+
+type imagesMsg struct {
+	viewID int64
+	images []Image
+	err    error
 }
 
 func (l listImagesModel) Init() tea.Cmd {
-	return tea.SetWindowTitle("List Images")
+	return tea.Batch(tea.SetWindowTitle("Images"), l.status.Tick(), l.fetchCmd(), tickCmd(l.viewID))
+}
+
+func (l listImagesModel) client() Docker {
+	return l.dockerClient
 }
 
 func (l listImagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
 	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		l.keys = newScrollKeys("images")
+		l.table.KeyMap = l.keys.tableKeyMap()
+		l.table.SetStyles(tableStyles())
+		return l, nil
+
+	case tea.WindowSizeMsg:
+		l.width = msg.Width
+		l.height = msg.Height
+		return l, nil
+
 	case tea.KeyMsg:
-		// Handle help
 		switch {
+
 		case key.Matches(msg, l.keys.Help):
-			l.help.ShowAll = !l.help.ShowAll
+			return l, showHelp("images", l.keys)
+
+		case key.Matches(msg, l.keys.Back):
+			return l, pop()
+
+		case key.Matches(msg, l.keys.Quit):
+			return l, tea.Quit
 		}
 
-		switch msg.String() {
-		case "esc":
-			m := InitIndexModel(l.dockerClient)
-			return m, m.Init()
-		case "up", "k":
-			if l.cursor > 0 {
-				l.cursor--
-			}
-		case "down", "j":
-			if l.cursor < len(l.containers)-1 {
-				l.cursor++
-			}
-		case "enter":
-			// return InitViewImagesModel(l.DB, l.Images[l.cursor], l.ImagesDownloadPercent[l.cursor], "/home/stardust/Downloads"), nil
+	case imagesMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
 		}
+		l.loading = false
+		l.status.StopLoading()
+		if msg.err != nil {
+			l.status.Error(msg.err)
+			return l, nil
+		}
+		l.table.SetRows(imageRows(msg.images, time.Now()))
+		return l, nil
+
+	case pingMsg:
+		if msg.viewID != l.viewID || msg.err != nil {
+			return l, nil
+		}
+		l.status.Recovered()
+		cmd = l.refresh()
+		return l, cmd
+
+	case spinner.TickMsg:
+		l.status, cmd = l.status.Update(msg)
+		return l, cmd
+
+	case tickMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		cmd = l.refresh()
+		return l, tea.Batch(cmd, tickCmd(l.viewID))
+	}
+
+	l.table, cmd = l.table.Update(msg)
+
+	return l, cmd
+}
+
+// refresh asks for the images again unless a request is already on its
+// way. While the daemon is unreachable it only pings it.
+func (l *listImagesModel) refresh() tea.Cmd {
+	if l.status.Unreachable() {
+		return pingCmd(l.viewID, l.dockerClient)
+	}
+	if l.loading {
+		return nil
+	}
+	l.loading = true
+	return l.fetchCmd()
+}
+
+func (l listImagesModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient := l.viewID, l.dockerClient
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		images, err := FetchImages(ctx, dockerClient)
+		return imagesMsg{viewID: viewID, images: images, err: err}
 	}
-	return l, nil
 }
 
 func (l listImagesModel) View() string {
-	s := "\nList Images\n\n"
+	doc := strings.Builder{}
 
-	for i, Images := range l.containers {
-		cursor := " "
-		if l.cursor == i {
-			cursor = ">"
-		}
+	title := lipgloss.PlaceHorizontal(l.width, lipgloss.Left, ContainerTitleStyle.Render("IMAGES"))
 
-		s += fmt.Sprintf("%s %d. %s (%d%%)\n", cursor, i+1, Images, l.containersDownloadPercent[i])
+	doc.WriteString(title)
 
-		status := "Downloading"
-		if l.containersDownloadPercent[i] == 100 {
-			status = "Downloaded"
-		}
+	doc.WriteString("\n\n")
+
+	doc.WriteString(TableStyle.Render(l.table.View()) + "\n")
+
+	if status := l.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
 
-		s += "     Progress: "
+	doc.WriteString(helpLine(l.keys))
 
-		prog := progress.New(progress.WithScaledGradient("#FF7CCB", "#FDFF8C"))
-		s += prog.ViewAs(float64(l.containersDownloadPercent[i]))
-		s += "\n"
-		s += fmt.Sprintf("     Status: %s\n\n", status)
+	return doc.String()
+}
+
+func imageRows(images []Image, now time.Time) []table.Row {
+	rows := []table.Row{}
+	for _, i := range images {
+		rows = append(rows, table.Row{
+			i.Repository,
+			i.Tag,
+			shortImageID(i.ID),
+			units.HumanDuration(now.Sub(i.Created)) + " ago",
+			units.HumanSize(float64(i.Size)),
+		})
+	}
+	return rows
+}
+
+// shortImageID is the ID docker images shows, without the digest algorithm.
+func shortImageID(id string) string {
+	_, hex, ok := strings.Cut(id, ":")
+	if !ok {
+		hex = id
+	}
+	return hex[:min(12, len(hex))]
+}
+
+func FetchImages(ctx context.Context, dockerClient Docker) ([]Image, error) {
+	var summaries []imageTypes.Summary
+	err := retry(ctx, func() (err error) {
+		summaries, err = dockerClient.ImageList(ctx, imageTypes.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	helpView := l.help.View(l.keys)
-	height := 8 - strings.Count(helpView, "\n")
+	images := []Image{}
+	for _, s := range summaries {
+		image := Image{ID: s.ID, Created: time.Unix(s.Created, 0), Size: s.Size}
+		if len(s.RepoTags) == 0 {
+			image.Repository, image.Tag = "<none>", "<none>"
+			images = append(images, image)
+			continue
+		}
+		for _, ref := range s.RepoTags {
+			// The tag follows the last colon, a registry port comes before
+			// the last slash
+			image.Repository, image.Tag = ref, "<none>"
+			if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+				image.Repository, image.Tag = ref[:i], ref[i+1:]
+			}
+			images = append(images, image)
+		}
+	}
 
-	s += strings.Repeat("\n", height) + helpView
+	// Untagged images go last
+	sort.SliceStable(images, func(i, j int) bool {
+		if dangling := images[i].Repository == "<none>"; dangling != (images[j].Repository == "<none>") {
+			return !dangling
+		}
+		if images[i].Repository != images[j].Repository {
+			return images[i].Repository < images[j].Repository
+		}
+		return images[i].Tag < images[j].Tag
+	})
 
-	return s
+	return images, nil
 }
@@ -3,26 +3,59 @@ package src
 import (
 	"strings"
 
-	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/docker/go-sdk/client"
 )
 
 // index Model
 
 type indexModel struct {
-	help         help.Model
-	keys         keyMap
-	dockerClient client.SDKClient
+	keys         indexKeys
+	dockerClient Docker
 	width        int
 	height       int
 	table        table.Model
 }
 
-func InitIndexModel(dockerClient client.SDKClient) indexModel {
+type indexKeys struct {
+	globalKeys
+	Select key.Binding
+}
+
+func defaultIndexKeys() indexKeys {
+	return indexKeys{
+		globalKeys: defaultGlobalKeys(),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+	}
+}
+
+func newIndexKeys() indexKeys {
+	k := defaultIndexKeys()
+	remap("global", &k.globalKeys)
+	remap("index", &k)
+	return k
+}
+
+func (k *indexKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"select": &k.Select,
+	}
+}
+
+func (k indexKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Command, k.Quit, k.Help}
+}
+
+func (k indexKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Select}}, k.globalKeys.FullHelp()...)
+}
+
+func InitIndexModel(dockerClient Docker) indexModel {
 	columns := []table.Column{
 		{Title: "Index", Width: 6},
 		{Title: "Menu Item", Width: 15},
@@ -31,68 +64,62 @@ func InitIndexModel(dockerClient client.SDKClient) indexModel {
 	rows := []table.Row{
 		{"0", "Containers"},
 		{"1", "Images"},
-		{"2", "Exit"},
+		{"2", "Networks"},
+		{"3", "Contexts"},
+		{"4", "Exit"},
 	}
 
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
+		table.WithKeyMap(tableKeyMap()),
 		table.WithHeight(7),
 	)
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
+	t.SetStyles(tableStyles())
 
 	return indexModel{
-		help:         help.New(),
-		keys:         keys,
+		keys:         newIndexKeys(),
 		dockerClient: dockerClient,
 		table:        t,
 	}
 }
 
-var baseStyle = lipgloss.NewStyle().
-	BorderStyle(lipgloss.NormalBorder()).
-	BorderForeground(lipgloss.Color("240")).
-	MarginLeft(4)
-
 func (m indexModel) Init() tea.Cmd {
 	return tea.SetWindowTitle("StarDocker")
 }
 
+func (m indexModel) client() Docker {
+	return m.dockerClient
+}
+
 func (m indexModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 
+	case configChangedMsg:
+		m.keys = newIndexKeys()
+		m.table.KeyMap = tableKeyMap()
+		m.table.SetStyles(tableStyles())
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
 
 	case tea.KeyMsg:
-		// Handle help
 		switch {
-		case key.Matches(msg, m.keys.Help):
-			m.help.ShowAll = !m.help.ShowAll
-		}
 
-		switch msg.String() {
+		case key.Matches(msg, m.keys.Help):
+			return m, showHelp("menu", m.keys)
 
-		case "q":
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
-		case "enter":
+		case key.Matches(msg, m.keys.Select):
 			row := m.table.SelectedRow()
 
 			switch row[1] {
@@ -101,12 +128,18 @@ func (m indexModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 
 			case "Containers":
-				l := InitListContainersModel(m.dockerClient, m.width, m.height)
-				return l, l.Init()
+				return m, push(InitListContainersModel(m.dockerClient, m.width, m.height))
 
 			case "Images":
-				m := InitListImagesModel(m.dockerClient)
-				return m, m.Init()
+				return m, push(InitListImagesModel(m.dockerClient, m.width, m.height))
+
+			case "Networks":
+				return m, push(InitListNetworksModel(m.dockerClient, m.width, m.height))
+
+			case "Contexts":
+				return m, push(InitContextsModel(m.dockerClient, m.width, m.height, func(dockerClient Docker) []tea.Model {
+					return []tea.Model{InitIndexModel(dockerClient)}
+				}))
 			}
 		}
 	}
@@ -145,11 +178,9 @@ func (m indexModel) View() string {
 
 	doc.WriteString("\n\n")
 
-	doc.WriteString(baseStyle.Render(m.table.View()) + "\n")
-
-	helpView := m.help.View(m.keys)
+	doc.WriteString(MenuTableStyle.Render(m.table.View()) + "\n")
 
-	doc.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Left, HelpStyle.Render(helpView)))
+	doc.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Left, helpLine(m.keys)))
 
 	return doc.String()
 }
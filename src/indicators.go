@@ -0,0 +1,170 @@
+package src
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// The indicator column shows at a glance how a container is doing: one
+// glyph, in its own color, per state, running containers with a
+// healthcheck going by their health instead. A stack shows the indicator
+// its services share, or a mixed one when they don't agree. Terminals
+// without the glyphs get ASCII ones, see containers.indicators in the
+// config.
+
+const (
+	IndicatorsAuto    = "auto"
+	IndicatorsUnicode = "unicode"
+	IndicatorsASCII   = "ascii"
+)
+
+var indicatorModes = []string{IndicatorsAuto, IndicatorsUnicode, IndicatorsASCII}
+
+// indicator is how one state looks in the indicator column.
+type indicator struct {
+	glyph string
+	ascii string
+	// color picks the theme color it is drawn in
+	color func(t Theme) string
+}
+
+var (
+	runningIndicator     = indicator{"⏺", "*", func(t Theme) string { return t.Success }}
+	healthyIndicator     = indicator{"✔", "+", func(t Theme) string { return t.Success }}
+	startingIndicator    = indicator{"◐", "~", func(t Theme) string { return t.Warning }}
+	unhealthyIndicator   = indicator{"✖", "#", func(t Theme) string { return t.Error }}
+	pausedIndicator      = indicator{"⏸", "=", func(t Theme) string { return t.Secondary }}
+	restartingIndicator  = indicator{"↻", "@", func(t Theme) string { return t.Warning }}
+	exitedIndicator      = indicator{"○", "-", func(t Theme) string { return t.Border }}
+	crashedIndicator     = indicator{"✗", "x", func(t Theme) string { return t.Error }}
+	createdIndicator     = indicator{"◌", ".", func(t Theme) string { return t.Secondary }}
+	deadIndicator        = indicator{"†", "X", func(t Theme) string { return t.Error }}
+	mixedIndicator       = indicator{"◑", "%", func(t Theme) string { return t.Warning }}
+	unreachableIndicator = indicator{"!", "!", func(t Theme) string { return t.Error }}
+	noIndicator          = indicator{" ", " ", func(t Theme) string { return "" }}
+)
+
+var indicators = []indicator{
+	runningIndicator, healthyIndicator, startingIndicator, unhealthyIndicator,
+	pausedIndicator, restartingIndicator, exitedIndicator, crashedIndicator,
+	createdIndicator, deadIndicator, mixedIndicator, unreachableIndicator,
+}
+
+var stateIndicators = map[containerTypes.ContainerState]indicator{
+	containerTypes.StatePaused:     pausedIndicator,
+	containerTypes.StateRestarting: restartingIndicator,
+	containerTypes.StateCreated:    createdIndicator,
+	containerTypes.StateDead:       deadIndicator,
+	containerTypes.StateRemoving:   deadIndicator,
+}
+
+var healthIndicators = map[containerTypes.HealthStatus]indicator{
+	containerTypes.Healthy:   healthyIndicator,
+	containerTypes.Starting:  startingIndicator,
+	containerTypes.Unhealthy: unhealthyIndicator,
+}
+
+var exitCodePattern = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+
+func indicatorOf(c Container) indicator {
+	switch c.Type {
+	case TypeUnreachableHost:
+		return unreachableIndicator
+	case TypeComposeStack:
+		if len(c.Children) == 0 {
+			return noIndicator
+		}
+		shared := indicatorOf(c.Children[0])
+		for _, child := range c.Children[1:] {
+			if indicatorOf(child).glyph != shared.glyph {
+				return mixedIndicator
+			}
+		}
+		return shared
+	}
+
+	switch c.State {
+	case containerTypes.StateRunning:
+		if i, ok := healthIndicators[containerHealth(c)]; ok {
+			return i
+		}
+		return runningIndicator
+	case containerTypes.StateExited:
+		if m := exitCodePattern.FindStringSubmatch(c.Status); m != nil && m[1] != "0" {
+			return crashedIndicator
+		}
+		return exitedIndicator
+	}
+	if i, ok := stateIndicators[c.State]; ok {
+		return i
+	}
+	return noIndicator
+}
+
+func (i indicator) String() string {
+	if asciiIndicators() {
+		return i.ascii
+	}
+	return i.glyph
+}
+
+func containerIndicator(c Container) string {
+	return indicatorOf(c).String()
+}
+
+// asciiIndicators tells whether to stick to ASCII. auto does on the Linux
+// console and under a locale that isn't UTF-8.
+func asciiIndicators() bool {
+	switch CurrentConfig().Containers.Indicators {
+	case IndicatorsASCII:
+		return true
+	case IndicatorsUnicode:
+		return false
+	}
+	if os.Getenv("TERM") == "linux" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
+
+// colorIndicators colors the indicators of a rendered table whose
+// indicator column starts offset cells in. Table cells can't be styled, as
+// styles count towards their width, so this is done once the table is laid
+// out. The header is left alone, and so is the row under the cursor, which
+// has colors of its own.
+func colorIndicators(view string, offset int) string {
+	colors := map[string]string{}
+	for _, i := range indicators {
+		colors[i.String()] = i.color(theme)
+	}
+
+	lines := strings.Split(view, "\n")
+	for n, line := range lines {
+		if n < 2 || strings.Contains(line, "\x1b[") {
+			continue
+		}
+		at := 0
+		for j, r := range line {
+			if at < offset {
+				at += lipgloss.Width(string(r))
+				continue
+			}
+			if color := colors[string(r)]; color != "" {
+				glyph := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(string(r))
+				lines[n] = line[:j] + glyph + line[j+len(string(r)):]
+			}
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
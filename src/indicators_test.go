@@ -0,0 +1,86 @@
+package src
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestContainerIndicator(t *testing.T) {
+	running := Container{Type: TypeContainer, State: container.StateRunning, Status: "Up 2 hours"}
+	exited := Container{Type: TypeContainer, State: container.StateExited, Status: "Exited (0) 3 hours ago"}
+	crashed := Container{Type: TypeContainer, State: container.StateExited, Status: "Exited (137) 5 minutes ago"}
+	unhealthy := Container{Type: TypeContainer, State: container.StateRunning, Status: "Up 2 hours (unhealthy)"}
+
+	tests := []struct {
+		name  string
+		c     Container
+		glyph string
+		ascii string
+	}{
+		{"running", running, "⏺", "*"},
+		{"healthy", Container{Type: TypeContainer, State: container.StateRunning, Status: "Up 2 hours (healthy)"}, "✔", "+"},
+		{"starting", Container{Type: TypeContainer, State: container.StateRunning, Status: "Up 2 seconds (health: starting)"}, "◐", "~"},
+		{"unhealthy", unhealthy, "✖", "#"},
+		{"paused", Container{Type: TypeContainer, State: container.StatePaused, Status: "Up 2 hours (Paused)"}, "⏸", "="},
+		{"restarting", Container{Type: TypeContainer, State: container.StateRestarting, Status: "Restarting (1) 2 seconds ago"}, "↻", "@"},
+		{"exited", exited, "○", "-"},
+		{"crashed", crashed, "✗", "x"},
+		{"created", Container{Type: TypeContainer, State: container.StateCreated, Status: "Created"}, "◌", "."},
+		{"dead", Container{Type: TypeContainer, State: container.StateDead, Status: "Dead"}, "†", "X"},
+		{"stack agreeing", Container{Type: TypeComposeStack, Children: []Container{running, running}}, "⏺", "*"},
+		{"stack mixed", Container{Type: TypeComposeStack, Children: []Container{running, unhealthy}}, "◑", "%"},
+		{"empty stack", Container{Type: TypeComposeStack}, " ", " "},
+		{"unreachable", Container{Type: TypeUnreachableHost}, "!", "!"},
+	}
+
+	c := DefaultConfig()
+	for _, mode := range []string{IndicatorsUnicode, IndicatorsASCII} {
+		c.Containers.Indicators = mode
+		useConfig(t, c)
+		for _, tt := range tests {
+			want := tt.glyph
+			if mode == IndicatorsASCII {
+				want = tt.ascii
+			}
+			if got := containerIndicator(tt.c); got != want {
+				t.Errorf("%s in %s: indicator %q, want %q", tt.name, mode, got, want)
+			}
+		}
+	}
+}
+
+func TestASCIIIndicators(t *testing.T) {
+	tests := []struct {
+		term, locale string
+		want         bool
+	}{
+		{"xterm-256color", "en_US.UTF-8", false},
+		{"xterm-256color", "de_DE.utf8", false},
+		{"xterm-256color", "C", true},
+		{"xterm-256color", "", false},
+		{"linux", "en_US.UTF-8", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("LC_ALL", tt.locale)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", "")
+		if got := asciiIndicators(); got != tt.want {
+			t.Errorf("TERM=%s LC_ALL=%s: ascii %v, want %v", tt.term, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestIndicatorsTUI(t *testing.T) {
+	c := DefaultConfig()
+	c.Containers.Indicators = IndicatorsASCII
+	useConfig(t, c)
+
+	f := testDocker()
+	f.addContainer("5e4d3c2b1a0f9e8d7c6b", "cron", "alpine:3.20", container.StatePaused, "Up 2 days (Paused)", "")
+	f.addContainer("9a8b7c6d5e4f3a2b1c0d", "shop-migrate", "shop/api:dev", container.StateExited, "Exited (0) 3 hours ago", "shop")
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("down", "down", "enter")
+	u.golden()
+}
@@ -0,0 +1,125 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DaemonLauncher knows how to bring up a Docker daemon on this machine when
+// one isn't answering.
+type DaemonLauncher interface {
+	Name() string
+	Launch(ctx context.Context) error
+}
+
+const (
+	LauncherAuto     = "auto"
+	LauncherDesktop  = "desktop"
+	LauncherSystemd  = "systemd"
+	LauncherRootless = "rootless"
+	LauncherNone     = "none"
+)
+
+var LauncherNames = []string{LauncherAuto, LauncherDesktop, LauncherSystemd, LauncherRootless, LauncherNone}
+
+// commandLauncher runs a single command to start the daemon. Detached
+// commands (GUI apps, daemons run in the foreground) are started and left
+// running; the rest are waited on so their errors can be reported.
+type commandLauncher struct {
+	name    string
+	command []string
+	detach  bool
+}
+
+func (c commandLauncher) Name() string {
+	return c.name
+}
+
+func (c commandLauncher) Launch(ctx context.Context) error {
+	if c.detach {
+		// Not tied to ctx, the daemon has to outlive the launch
+		return exec.Command(c.command[0], c.command[1:]...).Start()
+	}
+
+	output, err := exec.CommandContext(ctx, c.command[0], c.command[1:]...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", strings.Join(c.command, " "), err, msg)
+		}
+		return fmt.Errorf("%s: %w", strings.Join(c.command, " "), err)
+	}
+
+	return nil
+}
+
+// noneLauncher leaves starting the daemon to someone else and only waits.
+type noneLauncher struct{}
+
+func (noneLauncher) Name() string {
+	return LauncherNone
+}
+
+func (noneLauncher) Launch(ctx context.Context) error {
+	return nil
+}
+
+// NewDaemonLauncher returns the launcher registered under name. "auto" picks
+// the usual way of running Docker on the current OS.
+func NewDaemonLauncher(name string) (DaemonLauncher, error) {
+	if name == LauncherAuto {
+		switch runtime.GOOS {
+		case "darwin", "windows":
+			name = LauncherDesktop
+		case "linux":
+			name = LauncherSystemd
+			if _, err := exec.LookPath("systemctl"); err != nil {
+				name = LauncherNone
+			}
+		default:
+			name = LauncherNone
+		}
+	}
+
+	switch name {
+
+	case LauncherDesktop:
+		if runtime.GOOS == "windows" {
+			return commandLauncher{
+				name:    LauncherDesktop,
+				command: []string{"cmd", "/c", "start", "", `C:\Program Files\Docker\Docker\Docker Desktop.exe`},
+				detach:  true,
+			}, nil
+		}
+		return commandLauncher{
+			name:    LauncherDesktop,
+			command: []string{"open", "-a", "Docker", "--args", "--unattended"},
+		}, nil
+
+	case LauncherSystemd:
+		return commandLauncher{
+			name:    LauncherSystemd,
+			command: []string{"systemctl", "start", "docker"},
+		}, nil
+
+	case LauncherRootless:
+		if _, err := exec.LookPath("systemctl"); err == nil {
+			return commandLauncher{
+				name:    LauncherRootless,
+				command: []string{"systemctl", "--user", "start", "docker"},
+			}, nil
+		}
+		return commandLauncher{
+			name:    LauncherRootless,
+			command: []string{"dockerd-rootless.sh"},
+			detach:  true,
+		}, nil
+
+	case LauncherNone:
+		return noneLauncher{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown launcher %q, expected one of: %s", name, strings.Join(LauncherNames, ", "))
+}
@@ -0,0 +1,125 @@
+package src
+
+import "slices"
+
+// The containers table follows the size of the terminal. When it gets
+// narrow the minor columns are hidden first, ports and type before the
+// rest, then the flexible columns shrink, and only then are the others
+// hidden. Spare room goes to the flexible columns. Cells that still don't
+// fit are cut short with an ellipsis by the table.
+
+const (
+	// containersChrome is the height of everything around the rows of the
+	// containers table: title, borders, header, status and help
+	containersChrome = 10
+	// tableChrome is the width taken by the border and margin of a table
+	tableChrome = 3
+	// cellPadding is the padding around every cell
+	cellPadding = 2
+	// minFlexWidth is as narrow as flexible columns ever get
+	minFlexWidth = 8
+)
+
+// fullIDWidth fits a whole container ID.
+const fullIDWidth = 64
+
+// minorRanks are the hide ranks of columns that are hidden before the
+// flexible ones shrink.
+const minorRanks = 5
+
+// layoutColumns fits columns into a table width wide. A width of zero, the
+// size not being known yet, leaves them as they are.
+func layoutColumns(columns []containerColumn, width int) []containerColumn {
+	laid := slices.Clone(columns)
+	if width <= 0 {
+		return laid
+	}
+	available := width - tableChrome
+
+	// Hide columns, lowest rank first, until the rest fits. The minor ones
+	// go before anything shrinks, the others only when shrinking isn't
+	// enough.
+	for {
+		i := -1
+		for j, c := range laid {
+			if c.hideRank > 0 && (i < 0 || c.hideRank < laid[i].hideRank) {
+				i = j
+			}
+		}
+		if i < 0 {
+			break
+		}
+		if laid[i].hideRank < minorRanks && tableWidth(laid) <= available {
+			break
+		}
+		if laid[i].hideRank >= minorRanks && minTableWidth(laid) <= available {
+			break
+		}
+		laid = slices.Delete(laid, i, i+1)
+	}
+
+	flexible := []int{}
+	for i, c := range laid {
+		if c.flex {
+			flexible = append(flexible, i)
+		}
+	}
+	if len(flexible) == 0 {
+		return laid
+	}
+
+	// Share what is left over evenly, or take what is missing evenly down
+	// to the minimum widths, then further if nothing was left to hide
+	spare := available - tableWidth(laid)
+	if spare > 0 {
+		for n, i := range flexible {
+			laid[i].width += spare / len(flexible)
+			if n < spare%len(flexible) {
+				laid[i].width++
+			}
+		}
+		return laid
+	}
+	over := -spare
+	for _, floor := range []func(c containerColumn) int{minWidth, func(containerColumn) int { return minFlexWidth }} {
+		for over > 0 {
+			shrunk := false
+			for _, i := range flexible {
+				if over > 0 && laid[i].width > floor(laid[i]) {
+					laid[i].width--
+					over--
+					shrunk = true
+				}
+			}
+			if !shrunk {
+				break
+			}
+		}
+	}
+	return laid
+}
+
+// minWidth is as narrow as c gets before columns are hidden.
+func minWidth(c containerColumn) int {
+	if !c.flex {
+		return c.width
+	}
+	return max(minFlexWidth, c.width/2)
+}
+
+// tableWidth is how wide columns come out, borders left aside.
+func tableWidth(columns []containerColumn) int {
+	width := 0
+	for _, c := range columns {
+		width += c.width + cellPadding
+	}
+	return width
+}
+
+func minTableWidth(columns []containerColumn) int {
+	width := 0
+	for _, c := range columns {
+		width += minWidth(c) + cellPadding
+	}
+	return width
+}
@@ -0,0 +1,38 @@
+package src
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLayoutColumns(t *testing.T) {
+	columns := []containerColumn{}
+	for _, key := range DefaultConfig().Containers.Columns {
+		c, _ := containerColumnByKey(key)
+		columns = append(columns, c)
+	}
+
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, "[indicator:2 name:35 id:14 image:25 ports:24 status:32 state:10 health:9 type:20]"},
+		{160, "[indicator:2 name:41 id:14 image:30 status:37 state:10 health:9]"},
+		{120, "[indicator:2 name:27 id:14 image:17 status:24 state:10 health:9]"},
+		{80, "[indicator:2 name:22 image:13 status:20 state:10]"},
+		{40, "[indicator:2 name:19 state:10]"},
+		{10, "[indicator:2 name:8]"},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, c := range layoutColumns(columns, tt.width) {
+			got = append(got, fmt.Sprintf("%s:%d", c.key, c.width))
+		}
+		if fmt.Sprint(got) != tt.want {
+			t.Errorf("at %d columns: %v, want %v", tt.width, got, tt.want)
+		}
+		if tt.width >= 40 && tableWidth(layoutColumns(columns, tt.width))+tableChrome > tt.width {
+			t.Errorf("at %d columns the table doesn't fit", tt.width)
+		}
+	}
+}
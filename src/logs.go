@@ -1,17 +1,19 @@
 package src
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	containerTypes "github.com/docker/docker/api/types/container"
-	"github.com/docker/go-sdk/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/muesli/reflow/wordwrap"
 )
 
@@ -30,25 +32,45 @@ var (
 )
 
 type logsModel struct {
-	dockerClient  client.SDKClient
+	keys          scrollKeys
+	dockerClient  Docker
+	viewID        int64
 	containerID   string
 	containerName string
 	logs          string
+	loading       bool
+	status        statusBar
 	ready         bool
 	viewport      viewport.Model
 }
 
-func InitLogsModel(dockerClient client.SDKClient, containerID string, containerName string) logsModel {
-	return logsModel{
+func InitLogsModel(dockerClient Docker, containerID string, containerName string) logsModel {
+	l := logsModel{
+		keys:          newScrollKeys("logs"),
 		dockerClient:  dockerClient,
+		viewID:        nextViewID(),
 		containerID:   containerID,
 		containerName: containerName,
-		logs:          GetContainerLogs(dockerClient, containerID, false),
+		status:        newStatusBar(),
 	}
+	l.loading = true
+	l.status.StartLoading()
+
+	return l
+}
+
+type logsMsg struct {
+	viewID int64
+	logs   string
+	err    error
 }
 
 func (l logsModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(l.status.Tick(), l.fetchCmd(), tickCmd(l.viewID))
+}
+
+func (l logsModel) client() Docker {
+	return l.dockerClient
 }
 
 func (l logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -59,15 +81,25 @@ func (l logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 
+	case configChangedMsg:
+		l.keys = newScrollKeys("logs")
+		l.viewport.KeyMap = l.keys.viewportKeyMap()
+		return l, nil
+
 	case tea.KeyMsg:
-		if k := msg.String(); k == "ctrl+c" || k == "q" {
+		switch {
+		case key.Matches(msg, l.keys.Quit):
 			return l, tea.Quit
-		}
-
-		switch msg.String() {
-		case "esc":
-			m := InitListContainersModel(l.dockerClient, l.viewport.Width, l.viewport.Height)
-			return m, m.Init()
+		case key.Matches(msg, l.keys.Back):
+			return l, pop()
+		case key.Matches(msg, l.keys.Help):
+			return l, showHelp("logs", l.keys)
+		case key.Matches(msg, l.keys.Top):
+			l.viewport.GotoTop()
+			return l, nil
+		case key.Matches(msg, l.keys.Bottom):
+			l.viewport.GotoBottom()
+			return l, nil
 		}
 
 	case tea.WindowSizeMsg:
@@ -83,6 +115,7 @@ func (l logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// here.
 			l.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
 			l.viewport.YPosition = headerHeight
+			l.viewport.KeyMap = l.keys.viewportKeyMap()
 			l.viewport.SetContent(wordwrap.String(l.logs, msg.Width))
 			l.viewport.GotoBottom()
 			l.ready = true
@@ -91,13 +124,44 @@ func (l logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			l.viewport.Height = msg.Height - verticalMarginHeight
 		}
 
+	case logsMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.loading = false
+		l.status.StopLoading()
+		if msg.err != nil {
+			// Keep showing what we had, the container may just be gone
+			l.status.Error(msg.err)
+			return l, nil
+		}
+		l.logs = msg.logs
+		if l.ready {
+			l.viewport.SetContent(wordwrap.String(l.logs, l.viewport.Width))
+			if l.viewport.ScrollPercent()*100 > 90 {
+				l.viewport.GotoBottom()
+			}
+		}
+		return l, nil
+
+	case pingMsg:
+		if msg.viewID != l.viewID || msg.err != nil {
+			return l, nil
+		}
+		l.status.Recovered()
+		cmd = l.refresh()
+		return l, cmd
+
+	case spinner.TickMsg:
+		l.status, cmd = l.status.Update(msg)
+		return l, cmd
+
 	case tickMsg:
-		l.logs = GetContainerLogs(l.dockerClient, l.containerID, true)
-		l.viewport.SetContent(wordwrap.String(l.logs, l.viewport.Width))
-		if l.viewport.ScrollPercent()*100 > 90 {
-			l.viewport.GotoBottom()
+		if msg.viewID != l.viewID {
+			return l, nil
 		}
-		return l, tickCmd()
+		cmd = l.refresh()
+		return l, tea.Batch(cmd, tickCmd(l.viewID))
 	}
 
 	// Handle keyboard and mouse events in the viewport
@@ -107,6 +171,30 @@ func (l logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return l, tea.Batch(cmds...)
 }
 
+// refresh asks for the logs again unless a request is already on its way.
+// While the daemon is unreachable it only pings it.
+func (l *logsModel) refresh() tea.Cmd {
+	if l.status.Unreachable() {
+		return pingCmd(l.viewID, l.dockerClient)
+	}
+	if l.loading {
+		return nil
+	}
+	l.loading = true
+	return l.fetchCmd()
+}
+
+func (l logsModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, containerID := l.viewID, l.dockerClient, l.containerID
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		logs, err := GetContainerLogs(ctx, dockerClient, containerID, true)
+		return logsMsg{viewID: viewID, logs: logs, err: err}
+	}
+}
+
 func (l logsModel) View() string {
 	if !l.ready {
 		return "\n  Initializing..."
@@ -123,21 +211,58 @@ func (l logsModel) headerView() string {
 func (l logsModel) footerView() string {
 	info := infoStyle.Render(fmt.Sprintf("%3.f%%", l.viewport.ScrollPercent()*100))
 	line := strings.Repeat("─", max(0, l.viewport.Width-lipgloss.Width(info)))
-	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
+	// The status line is always there so the viewport height stays put
+	return lipgloss.JoinHorizontal(lipgloss.Center, line, info) + "\n" + l.status.View()
 }
 
-func GetContainerLogs(dockerClient client.SDKClient, containerID string, refresh bool) string {
-	ctx := context.Background()
-	logs, err := dockerClient.ContainerLogs(ctx, containerID, containerTypes.LogsOptions{ShowStdout: true, ShowStderr: true, Since: "24h", Timestamps: true})
+func GetContainerLogs(ctx context.Context, dockerClient Docker, containerID string, refresh bool) (string, error) {
+	config := CurrentConfig().Logs
+	options := containerTypes.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: config.Timestamps,
+		Tail:       config.Tail,
+	}
+	if config.Since > 0 {
+		options.Since = config.Since.String()
+	}
+	return readLogs(ctx, dockerClient, containerID, options)
+}
+
+// readLogs reads the logs of containerID, stdout and stderr together.
+func readLogs(ctx context.Context, dockerClient Docker, containerID string, options containerTypes.LogsOptions) (string, error) {
+	inspected, err := InspectContainer(ctx, dockerClient, containerID)
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	defer logs.Close()
+	return readInspectedLogs(ctx, dockerClient, inspected, options)
+}
+
+// readInspectedLogs is readLogs for a container already inspected. Without a
+// TTY the daemon sends stdout and stderr in frames, which are taken apart.
+func readInspectedLogs(ctx context.Context, dockerClient Docker, inspected containerTypes.InspectResponse, options containerTypes.LogsOptions) (string, error) {
+	containerID := inspected.ID
+	tty := inspected.Config != nil && inspected.Config.Tty
 
-	data, err := io.ReadAll(logs)
+	data := bytes.Buffer{}
+	err := retry(ctx, func() error {
+		data.Reset()
+		logs, err := dockerClient.ContainerLogs(ctx, containerID, options)
+		if err != nil {
+			return err
+		}
+		defer logs.Close()
+
+		if tty {
+			_, err = io.Copy(&data, logs)
+		} else {
+			_, err = stdcopy.StdCopy(&data, &data, logs)
+		}
+		return err
+	})
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	return string(data)
+	return data.String(), nil
 }
@@ -0,0 +1,874 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+	networkTypes "github.com/docker/docker/api/types/network"
+)
+
+// List Networks Model
+
+type listNetworksModel struct {
+	keys         networksKeys
+	dockerClient Docker
+	viewID       int64
+	width        int
+	height       int
+	table        table.Model
+	input        textinput.Model
+	creating     bool
+	loading      bool
+	status       statusBar
+}
+
+type networksKeys struct {
+	globalKeys
+	Inspect  key.Binding
+	Topology key.Binding
+	Create   key.Binding
+	Remove   key.Binding
+	Contexts key.Binding
+}
+
+func defaultNetworksKeys() networksKeys {
+	return networksKeys{
+		globalKeys: defaultGlobalKeys(),
+		Inspect: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "inspect"),
+		),
+		Topology: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "topology"),
+		),
+		Create: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new network"),
+		),
+		Remove: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "remove"),
+		),
+		Contexts: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "context"),
+		),
+	}
+}
+
+func newNetworksKeys() networksKeys {
+	k := defaultNetworksKeys()
+	remap("global", &k.globalKeys)
+	remap("networks", &k)
+	mutating(&k.Create, &k.Remove)
+	return k
+}
+
+func (k *networksKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"inspect":  &k.Inspect,
+		"topology": &k.Topology,
+		"create":   &k.Create,
+		"remove":   &k.Remove,
+		"contexts": &k.Contexts,
+	}
+}
+
+func (k networksKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Inspect, k.Topology, k.Create, k.Remove, k.Contexts, k.Back, k.Help}
+}
+
+func (k networksKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Inspect, k.Topology, k.Create, k.Remove, k.Contexts}}, k.globalKeys.FullHelp()...)
+}
+
+const (
+	NetworkNameIndex = 0
+	NetworkIDIndex   = 1
+)
+
+type Network struct {
+	ID         string
+	Name       string
+	Driver     string
+	Scope      string
+	Subnet     string
+	Gateway    string
+	Internal   bool
+	Containers []NetworkEndpoint
+}
+
+type NetworkEndpoint struct {
+	ContainerID string
+	Name        string
+	IPv4Address string
+	IPv6Address string
+	MacAddress  string
+}
+
+func init() {
+	registerCommand(command{
+		name: "networks",
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			return push(InitListNetworksModel(env.dockerClient, env.width, env.height)), nil
+		},
+	})
+}
+
+func InitListNetworksModel(dockerClient Docker, width int, height int) listNetworksModel {
+	t := table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeyMap()),
+	)
+
+	t.SetStyles(tableStyles())
+
+	input := textinput.New()
+	input.Placeholder = "network name"
+	input.CharLimit = 64
+
+	l := listNetworksModel{
+		keys:         newNetworksKeys(),
+		dockerClient: dockerClient,
+		width:        width,
+		height:       height,
+		table:        t,
+		input:        input,
+		viewID:       nextViewID(),
+		status:       newStatusBar(),
+	}
+	l.resize()
+	l.loading = true
+	l.status.StartLoading()
+
+	return l
+}
+
+// resize fits the table to the screen, the name taking the spare width.
+func (l *listNetworksModel) resize() {
+	columns := []table.Column{
+		{Title: "Name", Width: 0},
+		{Title: "Network ID", Width: 15},
+		{Title: "Driver", Width: 10},
+		{Title: "Scope", Width: 8},
+		{Title: "Subnet", Width: 20},
+		{Title: "Gateway", Width: 16},
+		{Title: "Containers", Width: 10},
+	}
+	used := tableChrome
+	for _, c := range columns[1:] {
+		used += c.Width + cellPadding
+	}
+	columns[0].Width = max(minFlexWidth, l.width-used-cellPadding)
+
+	l.table.SetColumns(columns)
+	l.table.SetHeight(max(1, l.height-containersChrome))
+}
+
+type networksMsg struct {
+	viewID   int64
+	networks []Network
+	err      error
+}
+
+func (l listNetworksModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Networks"), l.status.Tick(), l.fetchCmd(), tickCmd(l.viewID))
+}
+
+func (l listNetworksModel) client() Docker {
+	return l.dockerClient
+}
+
+func (l listNetworksModel) typing() bool {
+	return l.creating
+}
+
+func (l listNetworksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		l.keys = newNetworksKeys()
+		l.table.KeyMap = tableKeyMap()
+		l.table.SetStyles(tableStyles())
+		return l, nil
+
+	case tea.WindowSizeMsg:
+		l.width = msg.Width
+		l.height = msg.Height
+		l.resize()
+		return l, nil
+
+	case tea.KeyMsg:
+		// While typing a name every key belongs to the input
+		if l.creating {
+			switch msg.String() {
+			case "esc":
+				l.creating = false
+				l.input.Blur()
+				l.input.Reset()
+			case "enter":
+				name := strings.TrimSpace(l.input.Value())
+				l.creating = false
+				l.input.Blur()
+				l.input.Reset()
+				if name != "" {
+					dockerClient := l.dockerClient
+					cmd = l.status.StartLoading()
+					return l, tea.Batch(cmd, actionCmd(l.viewID, fmt.Sprintf("Created network %s", name), func(ctx context.Context) error {
+						return CreateNetwork(ctx, dockerClient, name)
+					}))
+				}
+			default:
+				l.input, cmd = l.input.Update(msg)
+			}
+			return l, cmd
+		}
+
+		switch {
+
+		case key.Matches(msg, l.keys.Help):
+			return l, showHelp("networks", l.keys)
+
+		case key.Matches(msg, l.keys.Back):
+			return l, pop()
+
+		case key.Matches(msg, l.keys.Quit):
+			return l, tea.Quit
+
+		case key.Matches(msg, l.keys.Inspect):
+			row := l.table.SelectedRow()
+			if row == nil {
+				return l, nil
+			}
+			return l, push(InitNetworkModel(l.dockerClient, row[NetworkIDIndex], row[NetworkNameIndex], l.width, l.height))
+
+		case key.Matches(msg, l.keys.Contexts):
+			width, height := l.width, l.height
+			return l, push(InitContextsModel(l.dockerClient, width, height, func(dockerClient Docker) []tea.Model {
+				return []tea.Model{InitIndexModel(dockerClient), InitListNetworksModel(dockerClient, width, height)}
+			}))
+
+		case key.Matches(msg, l.keys.Topology):
+			return l, push(InitTopologyModel(l.dockerClient, l.width, l.height))
+
+		case key.Matches(msg, l.keys.Create):
+			l.creating = true
+			return l, l.input.Focus()
+
+		case key.Matches(msg, l.keys.Remove):
+			row := l.table.SelectedRow()
+			if row == nil {
+				return l, nil
+			}
+			dockerClient, networkID := l.dockerClient, row[NetworkIDIndex]
+			return l, confirm(confirmMsg{
+				question: fmt.Sprintf("Remove network %s?", row[NetworkNameIndex]),
+				yes: actionCmd(l.viewID, fmt.Sprintf("Removed network %s", row[NetworkNameIndex]), func(ctx context.Context) error {
+					return RemoveNetwork(ctx, dockerClient, networkID)
+				}),
+			})
+		}
+
+	case networksMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.loading = false
+		l.status.StopLoading()
+		if msg.err != nil {
+			l.status.Error(msg.err)
+			return l, nil
+		}
+		l.table.SetRows(l.getRows(msg.networks))
+		return l, nil
+
+	case actionMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.status.StopLoading()
+		if msg.err != nil {
+			l.status.Error(msg.err)
+		} else {
+			l.status.Info(msg.message)
+		}
+		cmd = l.refresh()
+		return l, cmd
+
+	case pingMsg:
+		if msg.viewID != l.viewID || msg.err != nil {
+			return l, nil
+		}
+		l.status.Recovered()
+		cmd = l.refresh()
+		return l, cmd
+
+	case spinner.TickMsg:
+		l.status, cmd = l.status.Update(msg)
+		return l, cmd
+
+	case tickMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		cmd = l.refresh()
+		return l, tea.Batch(cmd, tickCmd(l.viewID))
+	}
+
+	l.table, cmd = l.table.Update(msg)
+
+	return l, cmd
+}
+
+// refresh asks for the networks again unless a request is already on its
+// way. While the daemon is unreachable it only pings it.
+func (l *listNetworksModel) refresh() tea.Cmd {
+	if l.status.Unreachable() {
+		return pingCmd(l.viewID, l.dockerClient)
+	}
+	if l.loading {
+		return nil
+	}
+	l.loading = true
+	return l.fetchCmd()
+}
+
+func (l listNetworksModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient := l.viewID, l.dockerClient
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		networks, err := FetchNetworks(ctx, dockerClient)
+		return networksMsg{viewID: viewID, networks: networks, err: err}
+	}
+}
+
+func (l listNetworksModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(l.width, lipgloss.Left, ContainerTitleStyle.Render("NETWORKS"))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	doc.WriteString(TableStyle.Render(l.table.View()) + "\n")
+
+	if l.creating {
+		doc.WriteString(HelpStyle.Render("New network: "+l.input.View()) + "\n")
+	} else if status := l.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(l.keys))
+
+	return doc.String()
+}
+
+func (l listNetworksModel) getRows(networks []Network) []table.Row {
+	rows := []table.Row{}
+
+	for _, network := range networks {
+		rows = append(rows, table.Row{
+			network.Name,
+			network.ID,
+			network.Driver,
+			network.Scope,
+			network.Subnet,
+			network.Gateway,
+			fmt.Sprintf("%d", len(network.Containers)),
+		})
+	}
+
+	return rows
+}
+
+// Network Model
+
+type networkModel struct {
+	keys         networkKeys
+	dockerClient Docker
+	viewID       int64
+	networkID    string
+	networkName  string
+	network      Network
+	width        int
+	height       int
+	table        table.Model
+	input        textinput.Model
+	connecting   bool
+	loading      bool
+	status       statusBar
+}
+
+type networkKeys struct {
+	globalKeys
+	Connect    key.Binding
+	Disconnect key.Binding
+}
+
+func defaultNetworkKeys() networkKeys {
+	return networkKeys{
+		globalKeys: defaultGlobalKeys(),
+		Connect: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "connect container"),
+		),
+		Disconnect: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "disconnect selected"),
+		),
+	}
+}
+
+func newNetworkKeys() networkKeys {
+	k := defaultNetworkKeys()
+	remap("global", &k.globalKeys)
+	remap("network", &k)
+	mutating(&k.Connect, &k.Disconnect)
+	return k
+}
+
+func (k *networkKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"connect":    &k.Connect,
+		"disconnect": &k.Disconnect,
+	}
+}
+
+func (k networkKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Connect, k.Disconnect, k.Back, k.Help}
+}
+
+func (k networkKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Connect, k.Disconnect}}, k.globalKeys.FullHelp()...)
+}
+
+const NetworkEndpointNameIndex = 0
+
+// networkChrome is the height of everything around the containers table of
+// a network, the details of the network included.
+const networkChrome = containersChrome + 6
+
+func InitNetworkModel(dockerClient Docker, networkID string, networkName string, width int, height int) networkModel {
+	t := table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeyMap()),
+	)
+
+	t.SetStyles(tableStyles())
+
+	input := textinput.New()
+	input.Placeholder = "container name or ID"
+	input.CharLimit = 128
+
+	n := networkModel{
+		keys:         newNetworkKeys(),
+		dockerClient: dockerClient,
+		networkID:    networkID,
+		networkName:  networkName,
+		width:        width,
+		height:       height,
+		table:        t,
+		input:        input,
+		viewID:       nextViewID(),
+		status:       newStatusBar(),
+	}
+	n.resize()
+	n.loading = true
+	n.status.StartLoading()
+
+	return n
+}
+
+// resize fits the table to the screen, the container name taking the spare
+// width.
+func (n *networkModel) resize() {
+	columns := []table.Column{
+		{Title: "Container", Width: 0},
+		{Title: "Container ID", Width: 15},
+		{Title: "IPv4 Address", Width: 20},
+		{Title: "IPv6 Address", Width: 25},
+		{Title: "MAC Address", Width: 18},
+	}
+	used := tableChrome
+	for _, c := range columns[1:] {
+		used += c.Width + cellPadding
+	}
+	columns[0].Width = max(minFlexWidth, n.width-used-cellPadding)
+
+	n.table.SetColumns(columns)
+	n.table.SetHeight(max(1, n.height-networkChrome))
+}
+
+type networkMsg struct {
+	viewID  int64
+	network Network
+	err     error
+}
+
+func (n networkModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Network "+n.networkName), n.status.Tick(), n.fetchCmd(), tickCmd(n.viewID))
+}
+
+func (n networkModel) client() Docker {
+	return n.dockerClient
+}
+
+func (n networkModel) typing() bool {
+	return n.connecting
+}
+
+func (n networkModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		n.keys = newNetworkKeys()
+		n.table.KeyMap = tableKeyMap()
+		n.table.SetStyles(tableStyles())
+		return n, nil
+
+	case tea.WindowSizeMsg:
+		n.width = msg.Width
+		n.height = msg.Height
+		n.resize()
+		return n, nil
+
+	case tea.KeyMsg:
+		// While typing a container every key belongs to the input
+		if n.connecting {
+			switch msg.String() {
+			case "esc":
+				n.connecting = false
+				n.input.Blur()
+				n.input.Reset()
+			case "enter":
+				container := strings.TrimSpace(n.input.Value())
+				n.connecting = false
+				n.input.Blur()
+				n.input.Reset()
+				if container != "" {
+					dockerClient, networkID := n.dockerClient, n.networkID
+					cmd = n.status.StartLoading()
+					return n, tea.Batch(cmd, actionCmd(n.viewID, fmt.Sprintf("Connected %s to %s", container, n.networkName), func(ctx context.Context) error {
+						return ConnectNetwork(ctx, dockerClient, networkID, container)
+					}))
+				}
+			default:
+				n.input, cmd = n.input.Update(msg)
+			}
+			return n, cmd
+		}
+
+		switch {
+
+		case key.Matches(msg, n.keys.Help):
+			return n, showHelp("network "+n.networkName, n.keys)
+
+		case key.Matches(msg, n.keys.Back):
+			return n, pop()
+
+		case key.Matches(msg, n.keys.Quit):
+			return n, tea.Quit
+
+		case key.Matches(msg, n.keys.Connect):
+			n.connecting = true
+			return n, n.input.Focus()
+
+		case key.Matches(msg, n.keys.Disconnect):
+			row := n.table.SelectedRow()
+			if row == nil {
+				return n, nil
+			}
+			dockerClient, networkID, container := n.dockerClient, n.networkID, row[NetworkEndpointNameIndex]
+			return n, confirm(confirmMsg{
+				question: fmt.Sprintf("Disconnect %s from %s?", container, n.networkName),
+				yes: actionCmd(n.viewID, fmt.Sprintf("Disconnected %s from %s", container, n.networkName), func(ctx context.Context) error {
+					return DisconnectNetwork(ctx, dockerClient, networkID, container)
+				}),
+			})
+		}
+
+	case networkMsg:
+		if msg.viewID != n.viewID {
+			return n, nil
+		}
+		n.loading = false
+		n.status.StopLoading()
+		if msg.err != nil {
+			// Keep the last known state, the network may have been removed
+			// underneath us
+			n.status.Error(msg.err)
+			return n, nil
+		}
+		n.setNetwork(msg.network)
+		return n, nil
+
+	case actionMsg:
+		if msg.viewID != n.viewID {
+			return n, nil
+		}
+		n.status.StopLoading()
+		if msg.err != nil {
+			n.status.Error(msg.err)
+		} else {
+			n.status.Info(msg.message)
+		}
+		cmd = n.refresh()
+		return n, cmd
+
+	case pingMsg:
+		if msg.viewID != n.viewID || msg.err != nil {
+			return n, nil
+		}
+		n.status.Recovered()
+		cmd = n.refresh()
+		return n, cmd
+
+	case spinner.TickMsg:
+		n.status, cmd = n.status.Update(msg)
+		return n, cmd
+
+	case tickMsg:
+		if msg.viewID != n.viewID {
+			return n, nil
+		}
+		cmd = n.refresh()
+		return n, tea.Batch(cmd, tickCmd(n.viewID))
+	}
+
+	n.table, cmd = n.table.Update(msg)
+
+	return n, cmd
+}
+
+// refresh re-inspects the network unless a request is already on its way.
+// While the daemon is unreachable it only pings it.
+func (n *networkModel) refresh() tea.Cmd {
+	if n.status.Unreachable() {
+		return pingCmd(n.viewID, n.dockerClient)
+	}
+	if n.loading {
+		return nil
+	}
+	n.loading = true
+	return n.fetchCmd()
+}
+
+func (n networkModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, networkID := n.viewID, n.dockerClient, n.networkID
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		network, err := InspectNetwork(ctx, dockerClient, networkID)
+		return networkMsg{viewID: viewID, network: network, err: err}
+	}
+}
+
+func (n *networkModel) setNetwork(network Network) {
+	n.network = network
+
+	rows := []table.Row{}
+	for _, endpoint := range network.Containers {
+		rows = append(rows, table.Row{
+			endpoint.Name,
+			endpoint.ContainerID,
+			endpoint.IPv4Address,
+			endpoint.IPv6Address,
+			endpoint.MacAddress,
+		})
+	}
+	n.table.SetRows(rows)
+}
+
+func (n networkModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(n.width, lipgloss.Left, ContainerTitleStyle.Render("NETWORK "+n.networkName))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	internal := "no"
+	if n.network.Internal {
+		internal = "yes"
+	}
+	details := fmt.Sprintf(
+		"ID:       %s\nDriver:   %s\nScope:    %s\nSubnet:   %s\nGateway:  %s\nInternal: %s",
+		n.network.ID, n.network.Driver, n.network.Scope, n.network.Subnet, n.network.Gateway, internal,
+	)
+	doc.WriteString(HelpStyle.Render(details) + "\n\n")
+
+	doc.WriteString(TableStyle.Render(n.table.View()) + "\n")
+
+	if n.connecting {
+		doc.WriteString(HelpStyle.Render("Connect container: "+n.input.View()) + "\n")
+	} else if status := n.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(n.keys))
+
+	return doc.String()
+}
+
+// FetchNetworks lists the networks with the containers attached to them.
+// NetworkList leaves the containers out, and inspecting every network on
+// each refresh would cost a call per network, so they are read off the
+// container list instead.
+func FetchNetworks(ctx context.Context, dockerClient Docker) ([]Network, error) {
+	var networks []networkTypes.Summary
+	err := retry(ctx, func() (err error) {
+		networks, err = dockerClient.NetworkList(ctx, networkTypes.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []containerTypes.Summary
+	err = retry(ctx, func() (err error) {
+		containers, err = dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := map[string]map[string]networkTypes.EndpointResource{}
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for _, settings := range c.NetworkSettings.Networks {
+			if settings == nil || settings.NetworkID == "" {
+				continue
+			}
+			if endpoints[settings.NetworkID] == nil {
+				endpoints[settings.NetworkID] = map[string]networkTypes.EndpointResource{}
+			}
+			endpoints[settings.NetworkID][c.ID] = endpointResource(c, settings)
+		}
+	}
+
+	allNetworks := make([]Network, 0, len(networks))
+
+	for _, network := range networks {
+		network.Containers = endpoints[network.ID]
+		allNetworks = append(allNetworks, toNetwork(network))
+	}
+
+	sort.Slice(allNetworks, func(i, j int) bool { return allNetworks[i].Name < allNetworks[j].Name })
+
+	return allNetworks, nil
+}
+
+// endpointResource is how NetworkInspect would describe the endpoint of c
+// that settings belongs to.
+func endpointResource(c containerTypes.Summary, settings *networkTypes.EndpointSettings) networkTypes.EndpointResource {
+	endpoint := networkTypes.EndpointResource{
+		EndpointID: settings.EndpointID,
+		MacAddress: settings.MacAddress,
+	}
+	if len(c.Names) > 0 {
+		endpoint.Name = strings.TrimLeft(c.Names[0], "/")
+	}
+	if settings.IPAddress != "" {
+		endpoint.IPv4Address = fmt.Sprintf("%s/%d", settings.IPAddress, settings.IPPrefixLen)
+	}
+	if settings.GlobalIPv6Address != "" {
+		endpoint.IPv6Address = fmt.Sprintf("%s/%d", settings.GlobalIPv6Address, settings.GlobalIPv6PrefixLen)
+	}
+	return endpoint
+}
+
+func InspectNetwork(ctx context.Context, dockerClient Docker, networkID string) (Network, error) {
+	var network networkTypes.Inspect
+	err := retry(ctx, func() (err error) {
+		network, err = dockerClient.NetworkInspect(ctx, networkID, networkTypes.InspectOptions{})
+		return err
+	})
+	if err != nil {
+		return Network{}, err
+	}
+
+	return toNetwork(network), nil
+}
+
+func toNetwork(network networkTypes.Inspect) Network {
+	n := Network{
+		ID:       shortID(network.ID),
+		Name:     network.Name,
+		Driver:   network.Driver,
+		Scope:    network.Scope,
+		Internal: network.Internal,
+	}
+
+	subnets := []string{}
+	gateways := []string{}
+	for _, config := range network.IPAM.Config {
+		if config.Subnet != "" {
+			subnets = append(subnets, config.Subnet)
+		}
+		if config.Gateway != "" {
+			gateways = append(gateways, config.Gateway)
+		}
+	}
+	n.Subnet = strings.Join(subnets, ", ")
+	n.Gateway = strings.Join(gateways, ", ")
+
+	for containerID, endpoint := range network.Containers {
+		n.Containers = append(n.Containers, NetworkEndpoint{
+			ContainerID: shortID(containerID),
+			Name:        endpoint.Name,
+			IPv4Address: endpoint.IPv4Address,
+			IPv6Address: endpoint.IPv6Address,
+			MacAddress:  endpoint.MacAddress,
+		})
+	}
+	sort.Slice(n.Containers, func(i, j int) bool { return n.Containers[i].Name < n.Containers[j].Name })
+
+	return n
+}
+
+func CreateNetwork(ctx context.Context, dockerClient Docker, name string) error {
+	_, err := dockerClient.NetworkCreate(ctx, name, networkTypes.CreateOptions{Driver: "bridge", Labels: map[string]string{}})
+	return err
+}
+
+func RemoveNetwork(ctx context.Context, dockerClient Docker, networkID string) error {
+	return dockerClient.NetworkRemove(ctx, networkID)
+}
+
+func ConnectNetwork(ctx context.Context, dockerClient Docker, networkID string, container string) error {
+	return dockerClient.NetworkConnect(ctx, networkID, container, nil)
+}
+
+func DisconnectNetwork(ctx context.Context, dockerClient Docker, networkID string, container string) error {
+	return dockerClient.NetworkDisconnect(ctx, networkID, container, false)
+}
+
+// shortID trims a Docker ID to the 12 characters the docker CLI shows.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,59 @@
+package src
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/network"
+)
+
+func TestFetchNetworks(t *testing.T) {
+	ctx := context.Background()
+	f := testDocker()
+	created, err := f.NetworkCreate(ctx, "shop_default", network.CreateOptions{Driver: "bridge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"shop-api", "shop-db"} {
+		if err := f.NetworkConnect(ctx, created.ID, name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	networks, err := FetchNetworks(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || len(networks[0].Containers) != 2 {
+		t.Fatalf("networks = %+v, want shop_default with 2 containers", networks)
+	}
+	if got := networks[0].Containers[0]; got.Name != "shop-api" || got.ContainerID != "a1b2c3d4e5f6" || got.IPv4Address != "172.18.0.2/16" {
+		t.Errorf("first endpoint = %+v", got)
+	}
+	if f.networkInspects != 0 {
+		t.Errorf("listing networks inspected %d of them", f.networkInspects)
+	}
+}
+
+func TestNetworksResize(t *testing.T) {
+	var l tea.Model = InitListNetworksModel(testDocker(), testWidth, testHeight)
+	var n tea.Model = InitNetworkModel(testDocker(), "3c2b1a0f9e8d", "shop_default", testWidth, testHeight)
+
+	l, _ = l.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	n, _ = n.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+
+	// Resized, the tables are what they'd be opened at that size
+	got, want := l.(listNetworksModel).table, InitListNetworksModel(testDocker(), 120, 20).table
+	if !slices.Equal(got.Columns(), want.Columns()) || got.Height() != want.Height() {
+		t.Errorf("networks table is %v high %d, want %v high %d", got.Columns(), got.Height(), want.Columns(), want.Height())
+	}
+	if want.Columns()[0].Width == InitListNetworksModel(testDocker(), testWidth, testHeight).table.Columns()[0].Width {
+		t.Error("the network names didn't narrow with the screen")
+	}
+	got, want = n.(networkModel).table, InitNetworkModel(testDocker(), "3c2b1a0f9e8d", "shop_default", 120, 20).table
+	if !slices.Equal(got.Columns(), want.Columns()) || got.Height() != want.Height() {
+		t.Errorf("network table is %v high %d, want %v high %d", got.Columns(), got.Height(), want.Columns(), want.Height())
+	}
+}
@@ -0,0 +1,152 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The command palette opens on ":" from any screen talking to Docker, e.g.
+// ":logs nginx" or ":filter label=com.example.team=web". Screens register
+// their commands from an init function; the router owns the palette and
+// hands every command the client of the current context and the size of
+// the window.
+
+// command is something the palette can run.
+type command struct {
+	name string
+	// arg names the argument, empty when there is none
+	arg string
+	// optional commands run without their argument too
+	optional bool
+	// mutates is set on commands that change anything, which read-only
+	// mode turns off
+	mutates bool
+	// complete lists the values arg can take, for completion
+	complete func(ctx context.Context, env commandEnv) []string
+	run      func(env commandEnv, arg string) (tea.Cmd, error)
+}
+
+// commandEnv is the screen a command was typed on.
+type commandEnv struct {
+	dockerClient Docker
+	width        int
+	height       int
+	top          tea.Model
+}
+
+var commands = map[string]command{}
+
+func registerCommand(c command) {
+	if _, ok := commands[c.name]; ok {
+		panic("command registered twice: " + c.name)
+	}
+	commands[c.name] = c
+}
+
+// dockerScreen is a screen talking to a daemon.
+type dockerScreen interface {
+	client() Docker
+}
+
+// typingScreen is a screen that may be busy with a text input, which keeps
+// the palette key for itself.
+type typingScreen interface {
+	typing() bool
+}
+
+// dispatch parses line, e.g. "logs nginx", and runs the command it names.
+func dispatch(env commandEnv, line string) (tea.Cmd, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	if name == "" {
+		return nil, nil
+	}
+
+	c, ok := commands[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", name)
+	}
+	if c.mutates && readOnly() {
+		return nil, fmt.Errorf(":%s is off in read-only mode", c.name)
+	}
+	if c.arg == "" && arg != "" {
+		return nil, fmt.Errorf("usage: :%s", c.name)
+	}
+	if c.arg != "" && !c.optional && arg == "" {
+		return nil, fmt.Errorf("usage: :%s <%s>", c.name, c.arg)
+	}
+	return c.run(env, arg)
+}
+
+// commandMsg reports how a command that ran in the background went.
+type commandMsg struct {
+	message string
+	err     error
+}
+
+// completionsMsg carries what the palette can complete to.
+type completionsMsg struct {
+	suggestions []string
+}
+
+// completionsCmd gathers every command with each value of its argument,
+// e.g. "logs nginx" for every container.
+func completionsCmd(env commandEnv) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		suggestions := []string{}
+		for _, name := range slices.Sorted(maps.Keys(commands)) {
+			c := commands[name]
+			if c.mutates && readOnly() {
+				continue
+			}
+			if c.arg == "" || c.optional {
+				suggestions = append(suggestions, name)
+			}
+			if c.complete == nil {
+				continue
+			}
+			for _, value := range c.complete(ctx, env) {
+				suggestions = append(suggestions, name+" "+value)
+			}
+		}
+		return completionsMsg{suggestions: suggestions}
+	}
+}
+
+func newPalette() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "command"
+	input.ShowSuggestions = true
+	input.CharLimit = 256
+	return input
+}
+
+// paletteHelp lists what the palette can complete to, or every command
+// with its argument while nothing matches.
+func paletteHelp(input textinput.Model, width int) string {
+	matched := input.MatchedSuggestions()
+	if len(matched) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(commands)) {
+			c := commands[name]
+			usage := name
+			if c.arg != "" {
+				usage += " <" + c.arg + ">"
+			}
+			matched = append(matched, usage)
+		}
+	}
+
+	line := strings.Join(matched, " · ")
+	return HelpStyle.Render(lipgloss.NewStyle().MaxWidth(max(0, width-4)).Render(line))
+}
@@ -0,0 +1,184 @@
+package src
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	containerTypes "github.com/docker/docker/api/types/container"
+	"github.com/muesli/termenv"
+)
+
+// Ports read like "0.0.0.0:8080→80/tcp". A port published on every IPv4
+// and every IPv6 address is listed once, the daemon reporting it for both.
+
+// formatPort writes p with arrow between the published and the container
+// port.
+func formatPort(p containerTypes.Port, arrow string) string {
+	if p.PublicPort == 0 {
+		return fmt.Sprintf("%d/%s", p.PrivatePort, p.Type)
+	}
+	ip := p.IP
+	if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+	return fmt.Sprintf("%s:%d%s%d/%s", ip, p.PublicPort, arrow, p.PrivatePort, p.Type)
+}
+
+// portsString lists ports for the containers table.
+func portsString(ports []containerTypes.Port) string {
+	formatted := []string{}
+	for _, p := range dedupePorts(ports) {
+		formatted = append(formatted, formatPort(p, "→"))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// dedupePorts drops the IPv6 twin of a port published on all addresses and
+// sorts the rest by container port.
+func dedupePorts(ports []containerTypes.Port) []containerTypes.Port {
+	type binding struct {
+		public  uint16
+		private uint16
+		proto   string
+	}
+	wildcard := map[binding]bool{}
+	for _, p := range ports {
+		if p.IP == "0.0.0.0" {
+			wildcard[binding{p.PublicPort, p.PrivatePort, p.Type}] = true
+		}
+	}
+
+	deduped := []containerTypes.Port{}
+	for _, p := range ports {
+		if p.IP == "::" && wildcard[binding{p.PublicPort, p.PrivatePort, p.Type}] {
+			continue
+		}
+		if !slices.Contains(deduped, p) {
+			deduped = append(deduped, p)
+		}
+	}
+	slices.SortStableFunc(deduped, func(a, b containerTypes.Port) int {
+		return cmp.Or(
+			cmp.Compare(a.PrivatePort, b.PrivatePort),
+			strings.Compare(a.Type, b.Type),
+			cmp.Compare(a.PublicPort, b.PublicPort),
+			strings.Compare(a.IP, b.IP),
+		)
+	})
+	return deduped
+}
+
+// httpPorts are container ports that usually speak HTTP, preferred when
+// picking the port to open.
+var httpPorts = []uint16{80, 443, 8080, 8443, 8000, 3000, 5000, 9000}
+
+// portURL is the address of the published HTTP port of c, the usual HTTP
+// ports first, then the lowest published TCP port.
+func portURL(c Container) (string, error) {
+	published := []containerTypes.Port{}
+	for _, p := range dedupePorts(c.Ports) {
+		if p.PublicPort != 0 && p.Type == "tcp" {
+			published = append(published, p)
+		}
+	}
+	if len(published) == 0 {
+		return "", fmt.Errorf("%s publishes no TCP port", c.Name)
+	}
+
+	port := published[0]
+	for _, p := range published {
+		if slices.Contains(httpPorts, p.PrivatePort) {
+			port = p
+			break
+		}
+	}
+
+	scheme := "http"
+	if port.PrivatePort == 443 || port.PrivatePort == 8443 {
+		scheme = "https"
+	}
+	host := port.IP
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = hostAddress(c.Host)
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, host, port.PublicPort), nil
+}
+
+// hostAddress is where the ports published by the daemon of host can be
+// reached: the address of an endpoint reached over tcp or ssh, directly or
+// through its context, and localhost for the local daemon and sockets.
+func hostAddress(host string) string {
+	h := RemoteHost(host)
+	if h == nil {
+		return "localhost"
+	}
+	daemon := h.Endpoint.Host
+	if !strings.Contains(daemon, "://") {
+		e, err := contextEndpoint(daemon)
+		if err != nil {
+			return "localhost"
+		}
+		daemon = e.Host
+	}
+	u, err := url.Parse(daemon)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "tcp" && u.Scheme != "ssh") {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// openURL opens url in the browser. Tests replace it.
+var openURL = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		if _, err := exec.LookPath("xdg-open"); err != nil {
+			return errors.New("no browser to open: xdg-open is not installed")
+		}
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// copyText puts text on the clipboard through the terminal, which works
+// over SSH too. The escape sequence is written while Bubble Tea has let go
+// of the terminal, so it can't land in the middle of a frame; done reports
+// how it went. Tests replace it.
+var copyText = func(text string, done func(err error) tea.Msg) tea.Cmd {
+	return tea.Exec(&clipboardCommand{text: text}, done)
+}
+
+// clipboardCommand writes the OSC 52 sequence for text to the terminal it
+// is handed by tea.Exec.
+type clipboardCommand struct {
+	text   string
+	stdout io.Writer
+}
+
+func (c *clipboardCommand) Run() error {
+	if c.stdout == nil {
+		return errors.New("no terminal to copy through")
+	}
+	termenv.NewOutput(c.stdout).Copy(c.text)
+	return nil
+}
+
+func (c *clipboardCommand) SetStdin(io.Reader)    {}
+func (c *clipboardCommand) SetStdout(w io.Writer) { c.stdout = w }
+func (c *clipboardCommand) SetStderr(io.Writer)   {}
@@ -0,0 +1,93 @@
+package src
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestPortsString(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []container.Port
+		want  string
+	}{
+		{"none", nil, ""},
+		{"unpublished", []container.Port{{PrivatePort: 5432, Type: "tcp"}}, "5432/tcp"},
+		{"IPv4 and IPv6", []container.Port{
+			{IP: "::", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+			{IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+		}, "0.0.0.0:8080→80/tcp"},
+		{"IPv6 only", []container.Port{{IP: "::1", PublicPort: 8080, PrivatePort: 80, Type: "tcp"}}, "[::1]:8080→80/tcp"},
+		{"sorted", []container.Port{
+			{IP: "0.0.0.0", PublicPort: 8443, PrivatePort: 443, Type: "tcp"},
+			{IP: "::", PublicPort: 8443, PrivatePort: 443, Type: "tcp"},
+			{IP: "127.0.0.1", PublicPort: 5353, PrivatePort: 53, Type: "udp"},
+			{IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+		}, "127.0.0.1:5353→53/udp, 0.0.0.0:8080→80/tcp, 0.0.0.0:8443→443/tcp"},
+	}
+	for _, tt := range tests {
+		if got := portsString(tt.ports); got != tt.want {
+			t.Errorf("%s: portsString = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPortURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []container.Port
+		want  string
+	}{
+		{"HTTP first", []container.Port{
+			{IP: "0.0.0.0", PublicPort: 5432, PrivatePort: 5432, Type: "tcp"},
+			{IP: "0.0.0.0", PublicPort: 3001, PrivatePort: 3000, Type: "tcp"},
+		}, "http://localhost:3001"},
+		{"lowest TCP port", []container.Port{
+			{IP: "0.0.0.0", PublicPort: 9999, PrivatePort: 9999, Type: "udp"},
+			{IP: "127.0.0.1", PublicPort: 6379, PrivatePort: 6379, Type: "tcp"},
+		}, "http://127.0.0.1:6379"},
+		{"HTTPS", []container.Port{{IP: "::", PublicPort: 8443, PrivatePort: 443, Type: "tcp"}}, "https://localhost:8443"},
+		{"unpublished", []container.Port{{PrivatePort: 80, Type: "tcp"}}, ""},
+	}
+	for _, tt := range tests {
+		got, err := portURL(Container{Name: "web", Ports: tt.ports})
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: portURL = %q, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: portURL = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestHostAddress(t *testing.T) {
+	useDockerContexts(t, map[string]string{
+		"prod":    "ssh://deploy@prod.example.com",
+		"sidecar": "unix:///run/user/1000/docker.sock",
+	})
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"ssh://ci.example.com:2222", "ci.example.com"},
+		{"tcp://10.0.0.5:2376", "10.0.0.5"},
+		{"unix:///var/run/docker.sock", "localhost"},
+		{"prod", "prod.example.com"},
+		{"sidecar", "localhost"},
+		{"missing", "localhost"},
+	}
+	for _, tt := range tests {
+		useRemoteHost(t, Endpoint{Name: "remote", Host: tt.host}, newFakeDocker())
+		if got := hostAddress("remote"); got != tt.want {
+			t.Errorf("hostAddress of %s = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+	if got := hostAddress(LocalHostName); got != "localhost" {
+		t.Errorf("hostAddress of the local daemon = %q, want localhost", got)
+	}
+}
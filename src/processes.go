@@ -0,0 +1,581 @@
+package src
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// The processes screen is docker top for one running container: what runs
+// inside it, refreshed like the other screens and sortable like the
+// containers table, with a key to send the process under the cursor a
+// signal. Signals go through an exec, as the daemon has no API for it.
+
+// psArgs are handed to ps by the daemon.
+var psArgs = []string{"-eo", "pid,user,pcpu,pmem,etimes,args"}
+
+// processSignals are the signals the signal key cycles through.
+var processSignals = []string{"TERM", "HUP", "INT", "QUIT", "KILL", "USR1", "USR2"}
+
+// execPollInterval is how often a running exec is looked in on.
+const execPollInterval = 100 * time.Millisecond
+
+// signalScript sends signal $1 to the process in the container whose
+// command line is $2. ps runs on the host, so the PIDs it lists aren't the
+// ones the container knows; the process is found again by its command line
+// instead, and, when $3 gives how many seconds ago it started, by the start
+// time in /proc/<pid>/stat, which both sides count from the same boot in
+// ticks of 1/100s. It exits with 2 when the process is gone and 3 when
+// more than one matches.
+const signalScript = `found=
+read up _ < /proc/uptime
+for dir in /proc/[0-9]*; do
+	[ "$(tr '\0' ' ' < "$dir/cmdline" 2>/dev/null)" = "$2 " ] || continue
+	if [ -n "$3" ]; then
+		stat=$(cat "$dir/stat" 2>/dev/null) || continue
+		set -- "$1" "$2" "$3" ${stat##*)}
+		age=$((${up%.*} - ${23} / 100 - $3))
+		[ "$age" -ge -2 ] && [ "$age" -le 2 ] || continue
+	fi
+	found="$found ${dir#/proc/}"
+done
+set -- "$1" $found
+[ $# -eq 1 ] && exit 2
+[ $# -gt 2 ] && exit 3
+kill -s "$1" "$2"`
+
+// process is a row of docker top. CPU and Mem are -1 and Started is zero
+// when ps didn't say.
+type process struct {
+	PID     string
+	User    string
+	CPU     float64
+	Mem     float64
+	Started time.Time
+	Command string
+}
+
+// parseTop reads the processes out of what docker top returned, going by
+// the column titles, which depend on the platform and the ps arguments.
+// Elapsed times are taken back from now.
+func parseTop(top containerTypes.TopResponse, now time.Time) []process {
+	column := func(titles ...string) int {
+		return slices.IndexFunc(top.Titles, func(t string) bool {
+			return slices.Contains(titles, strings.ToUpper(t))
+		})
+	}
+	pid, user, cpu := column("PID"), column("USER", "UID"), column("%CPU", "C", "CPU")
+	mem, elapsed := column("%MEM"), column("ELAPSED")
+	command := column("COMMAND", "CMD", "ARGS", "NAME")
+
+	field := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+	percent := func(row []string, i int) float64 {
+		v, err := strconv.ParseFloat(field(row, i), 64)
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+	started := func(row []string, i int) time.Time {
+		seconds, ok := parseElapsed(field(row, i))
+		if !ok {
+			return time.Time{}
+		}
+		return now.Add(-time.Duration(seconds) * time.Second)
+	}
+
+	processes := []process{}
+	for _, row := range top.Processes {
+		processes = append(processes, process{
+			PID:     field(row, pid),
+			User:    field(row, user),
+			CPU:     percent(row, cpu),
+			Mem:     percent(row, mem),
+			Started: started(row, elapsed),
+			Command: field(row, command),
+		})
+	}
+	return processes
+}
+
+// ListProcesses runs docker top on containerID.
+func ListProcesses(ctx context.Context, dockerClient Docker, containerID string) ([]process, error) {
+	var top containerTypes.TopResponse
+	err := retry(ctx, func() error {
+		var err error
+		top, err = dockerClient.ContainerTop(ctx, containerID, psArgs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseTop(top, time.Now()), nil
+}
+
+// parseElapsed reads the seconds ps lists under ELAPSED, either plainly as
+// etimes does or as etime's [[dd-]hh:]mm:ss.
+func parseElapsed(s string) (int, bool) {
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return seconds, true
+	}
+	days, clock, ok := strings.Cut(s, "-")
+	if !ok {
+		days, clock = "0", s
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(days)
+	if err != nil {
+		return 0, false
+	}
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+	// Days are 24 hours, the hours 60 minutes and so on
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, false
+		}
+		seconds = seconds*[]int{24, 60, 60}[i] + n
+	}
+	return seconds, true
+}
+
+// SignalProcess sends signal, e.g. "TERM", to p inside containerID.
+func SignalProcess(ctx context.Context, dockerClient Docker, containerID string, p process, signal string) error {
+	age := ""
+	if !p.Started.IsZero() {
+		age = strconv.Itoa(int(time.Since(p.Started).Seconds()))
+	}
+	created, err := dockerClient.ContainerExecCreate(ctx, containerID, containerTypes.ExecOptions{
+		Cmd: []string{"sh", "-c", signalScript, "sh", signal, p.Command, age},
+	})
+	if err != nil {
+		return err
+	}
+	if err := dockerClient.ContainerExecStart(ctx, created.ID, containerTypes.ExecStartOptions{Detach: true}); err != nil {
+		return err
+	}
+
+	for {
+		inspected, err := dockerClient.ContainerExecInspect(ctx, created.ID)
+		if err != nil {
+			return err
+		}
+		if !inspected.Running {
+			switch inspected.ExitCode {
+			case 0:
+				return nil
+			case 2:
+				return fmt.Errorf("%s is not running any more", p.Command)
+			case 3:
+				return fmt.Errorf("more than one process runs %s", p.Command)
+			case 126, 127:
+				return fmt.Errorf("the container has no shell to send signals from")
+			}
+			return fmt.Errorf("kill exited with %d", inspected.ExitCode)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(execPollInterval):
+		}
+	}
+}
+
+// processSort is the order of the processes table.
+type processSort struct {
+	Key  string
+	Desc bool
+}
+
+// processSortKeys are the orders the sort key cycles through.
+var processSortKeys = []string{"cpu", "mem", "pid", "user", "command"}
+
+var defaultProcessSort = processSort{Key: "cpu", Desc: true}
+
+func (s processSort) next() processSort {
+	i := slices.Index(processSortKeys, s.Key)
+	return processSort{Key: processSortKeys[(i+1)%len(processSortKeys)]}
+}
+
+func (s processSort) reversed() processSort {
+	s.Desc = !s.Desc
+	return s
+}
+
+func (s processSort) String() string {
+	if s.Desc {
+		return s.Key + " ↓"
+	}
+	return s.Key + " ↑"
+}
+
+var processCompare = map[string]func(a, b process) int{
+	"cpu": func(a, b process) int { return cmp.Compare(a.CPU, b.CPU) },
+	"mem": func(a, b process) int { return cmp.Compare(a.Mem, b.Mem) },
+	"pid": func(a, b process) int {
+		x, _ := strconv.Atoi(a.PID)
+		y, _ := strconv.Atoi(b.PID)
+		return cmp.Compare(x, y)
+	},
+	"user":    func(a, b process) int { return strings.Compare(a.User, b.User) },
+	"command": func(a, b process) int { return strings.Compare(a.Command, b.Command) },
+}
+
+// sortProcesses returns processes in the order of s. Ties go by PID.
+func sortProcesses(processes []process, s processSort) []process {
+	sorted := slices.Clone(processes)
+	compare := processCompare[s.Key]
+	slices.SortStableFunc(sorted, func(a, b process) int {
+		n := compare(a, b)
+		if s.Desc {
+			n = -n
+		}
+		if n == 0 {
+			n = processCompare["pid"](a, b)
+		}
+		return n
+	})
+	return sorted
+}
+
+func formatPercent(v float64) string {
+	if v < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", v)
+}
+
+// Processes Model
+
+type processesKeys struct {
+	globalKeys
+	Sort    key.Binding
+	Reverse key.Binding
+	Signal  key.Binding
+	Kill    key.Binding
+}
+
+func defaultProcessesKeys() processesKeys {
+	return processesKeys{
+		globalKeys: defaultGlobalKeys(),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort by the next column"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "reverse the sort"),
+		),
+		Signal: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "pick the signal"),
+		),
+		Kill: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "send the signal"),
+		),
+	}
+}
+
+func newProcessesKeys() processesKeys {
+	k := defaultProcessesKeys()
+	remap("global", &k.globalKeys)
+	remap("processes", &k)
+	mutating(&k.Kill)
+	return k
+}
+
+func (k *processesKeys) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"sort":    &k.Sort,
+		"reverse": &k.Reverse,
+		"signal":  &k.Signal,
+		"kill":    &k.Kill,
+	}
+}
+
+func (k processesKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Sort, k.Signal, k.Kill, k.Back, k.Help}
+}
+
+func (k processesKeys) FullHelp() [][]key.Binding {
+	return append([][]key.Binding{{k.Sort, k.Reverse, k.Signal, k.Kill}}, k.globalKeys.FullHelp()...)
+}
+
+type processesModel struct {
+	keys         processesKeys
+	dockerClient Docker
+	viewID       int64
+	container    Container
+	processes    []process
+	// shown are the processes in the order of the table
+	shown   []process
+	sort    processSort
+	signal  string
+	width   int
+	height  int
+	table   table.Model
+	loading bool
+	status  statusBar
+}
+
+func InitProcessesModel(dockerClient Docker, c Container, width int, height int) processesModel {
+	t := table.New(
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeyMap()),
+	)
+	t.SetStyles(tableStyles())
+
+	p := processesModel{
+		keys:         newProcessesKeys(),
+		dockerClient: dockerClient,
+		viewID:       nextViewID(),
+		container:    c,
+		sort:         defaultProcessSort,
+		signal:       processSignals[0],
+		width:        width,
+		height:       height,
+		table:        t,
+		status:       newStatusBar(),
+	}
+	p.resize()
+	p.loading = true
+	p.status.StartLoading()
+
+	return p
+}
+
+type processesMsg struct {
+	viewID    int64
+	processes []process
+	err       error
+}
+
+func (p processesModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Processes "+p.container.Name), p.status.Tick(), p.fetchCmd(), tickCmd(p.viewID))
+}
+
+func (p processesModel) client() Docker {
+	return p.dockerClient
+}
+
+func (p processesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		p.keys = newProcessesKeys()
+		p.table.KeyMap = tableKeyMap()
+		p.table.SetStyles(tableStyles())
+		return p, nil
+
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+		p.height = msg.Height
+		p.resize()
+		p.setRows()
+		return p, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, p.keys.Help):
+			return p, showHelp("processes "+p.container.Name, p.keys)
+		case key.Matches(msg, p.keys.Back):
+			return p, pop()
+		case key.Matches(msg, p.keys.Quit):
+			return p, tea.Quit
+
+		case key.Matches(msg, p.keys.Sort):
+			p.sort = p.sort.next()
+			p.setRows()
+			return p, nil
+
+		case key.Matches(msg, p.keys.Reverse):
+			p.sort = p.sort.reversed()
+			p.setRows()
+			return p, nil
+
+		case key.Matches(msg, p.keys.Signal):
+			i := slices.Index(processSignals, p.signal)
+			p.signal = processSignals[(i+1)%len(processSignals)]
+			return p, nil
+
+		case key.Matches(msg, p.keys.Kill):
+			cursor := p.table.Cursor()
+			if cursor < 0 || cursor >= len(p.shown) {
+				return p, nil
+			}
+			target, signal, dockerClient, c := p.shown[cursor], p.signal, p.dockerClient, p.container
+			viewID := p.viewID
+			return p, confirm(confirmMsg{
+				question: fmt.Sprintf("Send SIG%s to %s?", signal, target.PID),
+				details:  []string{target.Command},
+				yes: actionCmd(viewID, fmt.Sprintf("Sent SIG%s to %s", signal, target.PID), func(ctx context.Context) error {
+					client, err := hostClient(dockerClient, c.Host)
+					if err != nil {
+						return err
+					}
+					return SignalProcess(ctx, client, c.ID, target, signal)
+				}),
+			})
+		}
+
+	case processesMsg:
+		if msg.viewID != p.viewID {
+			return p, nil
+		}
+		p.loading = false
+		p.status.StopLoading()
+		if msg.err != nil {
+			// Keep the last processes, the container may have stopped
+			// underneath us
+			p.status.Error(msg.err)
+			return p, nil
+		}
+		p.processes = msg.processes
+		p.setRows()
+		return p, nil
+
+	case actionMsg:
+		if msg.viewID != p.viewID {
+			return p, nil
+		}
+		p.status.StopLoading()
+		if msg.err != nil {
+			p.status.Error(msg.err)
+		} else {
+			p.status.Info(msg.message)
+		}
+		cmd = p.refresh()
+		return p, cmd
+
+	case pingMsg:
+		if msg.viewID != p.viewID || msg.err != nil {
+			return p, nil
+		}
+		p.status.Recovered()
+		cmd = p.refresh()
+		return p, cmd
+
+	case spinner.TickMsg:
+		p.status, cmd = p.status.Update(msg)
+		return p, cmd
+
+	case tickMsg:
+		if msg.viewID != p.viewID {
+			return p, nil
+		}
+		cmd = p.refresh()
+		return p, tea.Batch(cmd, tickCmd(p.viewID))
+	}
+
+	p.table, cmd = p.table.Update(msg)
+
+	return p, cmd
+}
+
+// refresh lists the processes again unless a request is already on its
+// way. While the daemon is unreachable it only pings it.
+func (p *processesModel) refresh() tea.Cmd {
+	if p.status.Unreachable() {
+		return pingCmd(p.viewID, p.dockerClient)
+	}
+	if p.loading {
+		return nil
+	}
+	p.loading = true
+	return p.fetchCmd()
+}
+
+func (p processesModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient, c := p.viewID, p.dockerClient, p.container
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		client, err := hostClient(dockerClient, c.Host)
+		if err != nil {
+			return processesMsg{viewID: viewID, err: err}
+		}
+		processes, err := ListProcesses(ctx, client, c.ID)
+		return processesMsg{viewID: viewID, processes: processes, err: err}
+	}
+}
+
+// resize gives the command what the other columns leave.
+func (p *processesModel) resize() {
+	columns := []table.Column{
+		{Title: "PID", Width: 8},
+		{Title: "User", Width: 12},
+		{Title: "CPU", Width: 6},
+		{Title: "Mem", Width: 6},
+		{Title: "Command", Width: 0},
+	}
+	used := tableChrome
+	for _, c := range columns[:len(columns)-1] {
+		used += c.Width + cellPadding
+	}
+	columns[len(columns)-1].Width = max(minFlexWidth, p.width-used-cellPadding)
+
+	p.table.SetRows(nil)
+	p.table.SetColumns(columns)
+	p.table.SetHeight(max(1, p.height-containersChrome))
+}
+
+func (p *processesModel) setRows() {
+	p.shown = sortProcesses(p.processes, p.sort)
+	rows := []table.Row{}
+	for _, process := range p.shown {
+		rows = append(rows, table.Row{
+			process.PID,
+			process.User,
+			formatPercent(process.CPU),
+			formatPercent(process.Mem),
+			process.Command,
+		})
+	}
+	p.table.SetRows(rows)
+}
+
+func (p processesModel) View() string {
+	doc := strings.Builder{}
+
+	heading := fmt.Sprintf("PROCESSES %s · %d · by %s · SIG%s", p.container.Name, len(p.processes), p.sort, p.signal)
+	title := lipgloss.PlaceHorizontal(p.width, lipgloss.Left, ContainerTitleStyle.Render(heading))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	doc.WriteString(TableStyle.Render(p.table.View()) + "\n")
+
+	if status := p.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(p.keys))
+
+	return doc.String()
+}
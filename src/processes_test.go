@@ -0,0 +1,140 @@
+package src
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestParseTop(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		top  container.TopResponse
+		want []process
+	}{
+		{
+			"ps -eo",
+			container.TopResponse{
+				Titles:    []string{"PID", "USER", "%CPU", "%MEM", "COMMAND"},
+				Processes: [][]string{{"4242", "root", "0.3", "1.2", "nginx: master process nginx -g daemon off;"}},
+			},
+			[]process{{PID: "4242", User: "root", CPU: 0.3, Mem: 1.2, Command: "nginx: master process nginx -g daemon off;"}},
+		},
+		{
+			"etimes",
+			container.TopResponse{
+				Titles:    []string{"PID", "USER", "%CPU", "%MEM", "ELAPSED", "COMMAND"},
+				Processes: [][]string{{"4301", "nginx", "2.5", "0.2", "90", "nginx: worker process"}},
+			},
+			[]process{{PID: "4301", User: "nginx", CPU: 2.5, Mem: 0.2, Started: now.Add(-90 * time.Second), Command: "nginx: worker process"}},
+		},
+		{
+			"etime",
+			container.TopResponse{
+				Titles:    []string{"PID", "ELAPSED", "CMD"},
+				Processes: [][]string{{"4301", "1-02:03:04", "nginx: worker process"}, {"4302", "05:06", "nginx: worker process"}},
+			},
+			[]process{
+				{PID: "4301", CPU: -1, Mem: -1, Started: now.Add(-(26*time.Hour + 3*time.Minute + 4*time.Second)), Command: "nginx: worker process"},
+				{PID: "4302", CPU: -1, Mem: -1, Started: now.Add(-(5*time.Minute + 6*time.Second)), Command: "nginx: worker process"},
+			},
+		},
+		{
+			"ps -ef",
+			container.TopResponse{
+				Titles:    []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"},
+				Processes: [][]string{{"999", "5120", "5099", "2", "10:00", "?", "00:00:03", "postgres"}},
+			},
+			[]process{{PID: "5120", User: "999", CPU: 2, Mem: -1, Command: "postgres"}},
+		},
+		{
+			"windows",
+			container.TopResponse{
+				Titles:    []string{"Name", "PID", "CPU", "Private Working Set"},
+				Processes: [][]string{{"smss.exe", "1440", "00:00:00.031", "217.1kB"}},
+			},
+			[]process{{PID: "1440", CPU: -1, Mem: -1, Command: "smss.exe"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseTop(tt.top, now); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: parseTop = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortProcesses(t *testing.T) {
+	processes := []process{
+		{PID: "10", User: "www", CPU: 5, Mem: 1, Command: "worker"},
+		{PID: "9", User: "root", CPU: 0.1, Mem: 3, Command: "master"},
+		{PID: "11", User: "www", CPU: 5, Mem: 2, Command: "worker"},
+	}
+
+	tests := []struct {
+		sort processSort
+		want []string
+	}{
+		{defaultProcessSort, []string{"10", "11", "9"}},
+		{processSort{Key: "mem"}, []string{"10", "11", "9"}},
+		{processSort{Key: "pid"}, []string{"9", "10", "11"}},
+		{processSort{Key: "pid", Desc: true}, []string{"11", "10", "9"}},
+		{processSort{Key: "command"}, []string{"9", "10", "11"}},
+	}
+
+	for _, tt := range tests {
+		pids := []string{}
+		for _, p := range sortProcesses(processes, tt.sort) {
+			pids = append(pids, p.PID)
+		}
+		if !slices.Equal(pids, tt.want) {
+			t.Errorf("by %s: %v, want %v", tt.sort, pids, tt.want)
+		}
+	}
+}
+
+func TestSignalProcess(t *testing.T) {
+	worker := process{PID: "4301", Command: "nginx: worker process"}
+
+	tests := []struct {
+		exitCode int
+		want     string
+	}{
+		{0, ""},
+		{2, "nginx: worker process is not running any more"},
+		{3, "more than one process runs nginx: worker process"},
+		{126, "the container has no shell to send signals from"},
+		{127, "the container has no shell to send signals from"},
+		{1, "kill exited with 1"},
+	}
+
+	for _, tt := range tests {
+		f := testDocker()
+		f.execExit = tt.exitCode
+		err := SignalProcess(context.Background(), f, "6f1c2b7d9e0a4c3b8a7d", worker, "TERM")
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("exit %d: SignalProcess = %q, want %q", tt.exitCode, got, tt.want)
+		}
+	}
+
+	t.Run("started", func(t *testing.T) {
+		f := testDocker()
+		started := worker
+		started.Started = time.Now().Add(-90 * time.Second)
+		if err := SignalProcess(context.Background(), f, "6f1c2b7d9e0a4c3b8a7d", started, "TERM"); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"sh", "-c", signalScript, "sh", "TERM", "nginx: worker process", "90"}
+		if ran := f.ran(); len(ran) != 1 || !slices.Equal(ran[0].cmd, want) {
+			t.Errorf("ran %+v, want %q", ran, want)
+		}
+	})
+}
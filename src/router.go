@@ -0,0 +1,375 @@
+package src
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type pushMsg struct {
+	model tea.Model
+}
+
+type popMsg struct{}
+
+type resetMsg struct {
+	stack []tea.Model
+	// old is the client the screens thrown away talked to, if it is to be
+	// closed
+	old Docker
+}
+
+// push opens model on top of the current screen.
+func push(model tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return pushMsg{model: model}
+	}
+}
+
+// pop goes back to the previous screen.
+func pop() tea.Cmd {
+	return func() tea.Msg {
+		return popMsg{}
+	}
+}
+
+// reset throws the whole history away, e.g. after switching daemons, and
+// starts over with stack, the last screen being shown. old, if not nil, is
+// closed once nothing follows it any more.
+func reset(old Docker, stack ...tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return resetMsg{stack: stack, old: old}
+	}
+}
+
+// Router Model
+
+// routerModel is the root of the program. It owns a stack of screens: keys
+// and everything else go to the one on top, window sizes go to all of them,
+// and going back pops the stack so the previous screen comes back exactly
+// as it was left. Screens only poll Docker while on top; popping back runs
+// Init again to resume them.
+type routerModel struct {
+	stack  []tea.Model
+	width  int
+	height int
+	// configErr is why the config file last failed to reload, if it did
+	configErr error
+	keys      globalKeys
+	// help is the overlay being shown, if any
+	help *helpMsg
+	// confirm is the question being asked, if any
+	confirm *confirmDialog
+	// palette is the command line, open while commanding
+	palette    textinput.Model
+	commanding bool
+	// result is how the last command went, shown until the next key
+	result *commandMsg
+	// crashWatches follow the crashes on each daemon, by host name
+	crashWatches map[string]*crashSubscription
+	// crashRetry is set while a watchCrashesMsg is on its way
+	crashRetry bool
+}
+
+func InitRouterModel(first tea.Model) routerModel {
+	return routerModel{
+		stack:        []tea.Model{first},
+		keys:         newGlobalKeys(),
+		palette:      newPalette(),
+		crashWatches: map[string]*crashSubscription{},
+	}
+}
+
+func (r routerModel) Init() tea.Cmd {
+	return tea.Batch(r.top().Init(), watchConfigCmd(configModTime()), func() tea.Msg { return watchCrashesMsg{} })
+}
+
+// watchCrashes follows the deaths of containers on the daemon of the
+// current context and on every endpoint, starting over on a daemon whose
+// client changed. Endpoints that can't be reached yet are tried again in a
+// while.
+func (r *routerModel) watchCrashes() tea.Cmd {
+	clients := map[string]Docker{}
+	if dockerClient := r.dockerClient(); dockerClient != nil {
+		clients[LocalHostName] = dockerClient
+	}
+	pending := false
+	for _, h := range RemoteHosts() {
+		hostClient, err := h.Client()
+		if err != nil {
+			pending = true
+			continue
+		}
+		clients[h.Endpoint.Name] = hostClient
+	}
+
+	for host, sub := range r.crashWatches {
+		if clients[host] != sub.client {
+			sub.cancel()
+			delete(r.crashWatches, host)
+		}
+	}
+	cmds := []tea.Cmd{}
+	for host, dockerClient := range clients {
+		if _, ok := r.crashWatches[host]; ok {
+			continue
+		}
+		sub := subscribeCrashes(host, dockerClient)
+		r.crashWatches[host] = sub
+		cmds = append(cmds, sub.next())
+	}
+	if pending {
+		cmds = append(cmds, r.retryCrashWatch())
+	}
+	return tea.Batch(cmds...)
+}
+
+// retryCrashWatch calls watchCrashes again in a while, unless that is
+// already on its way.
+func (r *routerModel) retryCrashWatch() tea.Cmd {
+	if r.crashRetry {
+		return nil
+	}
+	r.crashRetry = true
+	return tea.Tick(crashWatchRetry, func(time.Time) tea.Msg { return watchCrashesMsg{} })
+}
+
+func (r routerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		r.width = msg.Width
+		r.height = msg.Height
+		return r, r.broadcast(msg)
+
+	case configMsg:
+		cmd := watchConfigCmd(msg.modTime)
+		if msg.err != nil {
+			// Keep running with the last good config until the file is fixed
+			r.configErr = msg.err
+			return r, cmd
+		}
+		if msg.config == nil {
+			return r, cmd
+		}
+		r.configErr = nil
+		applyConfig(*msg.config)
+		r.keys = newGlobalKeys()
+		return r, tea.Batch(cmd, r.broadcast(configChangedMsg{}))
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return r, tea.Quit
+		}
+		// Any key closes the help overlay without reaching the screen
+		if r.help != nil {
+			r.help = nil
+			return r, nil
+		}
+		if r.confirm != nil {
+			dialog, answered, cmd := r.confirm.update(msg)
+			r.confirm = &dialog
+			if answered {
+				r.confirm = nil
+			}
+			return r, cmd
+		}
+		r.result = nil
+		if r.commanding {
+			return r.updatePalette(msg)
+		}
+		if key.Matches(msg, r.keys.Command) {
+			if env, ok := r.commandEnv(); ok {
+				r.commanding = true
+				r.palette.Reset()
+				return r, tea.Batch(r.palette.Focus(), completionsCmd(env))
+			}
+		}
+
+	case completionsMsg:
+		r.palette.SetSuggestions(msg.suggestions)
+		return r, nil
+
+	case commandMsg:
+		r.result = &msg
+		return r, nil
+
+	case helpMsg:
+		r.help = &msg
+		return r, nil
+
+	case confirmMsg:
+		dialog, cmd := newConfirmDialog(msg)
+		r.confirm = &dialog
+		return r, cmd
+
+	case pushMsg:
+		m, sizeCmd := r.sized(msg.model)
+		r.stack = append(r.stack, m)
+		r.help = nil
+		return r, tea.Batch(m.Init(), sizeCmd, r.watchCrashes())
+
+	case popMsg:
+		if len(r.stack) == 1 {
+			return r, nil
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+		return r, r.top().Init()
+
+	case resetMsg:
+		cmds := []tea.Cmd{}
+		r.stack = make([]tea.Model, len(msg.stack))
+		for i, m := range msg.stack {
+			var cmd tea.Cmd
+			r.stack[i], cmd = r.sized(m)
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, r.top().Init(), r.watchCrashes())
+		// The crash watch on the old client was stopped above
+		if msg.old != nil {
+			msg.old.Close()
+		}
+		return r, tea.Batch(cmds...)
+
+	case crashEventMsg:
+		if msg.sub != r.crashWatches[msg.sub.host] {
+			return r, nil
+		}
+		crashes.record(msg.sub.host, msg.event)
+		return r, msg.sub.next()
+
+	case crashWatchEndedMsg:
+		if msg.sub != r.crashWatches[msg.sub.host] {
+			return r, nil
+		}
+		// Try again in a while; the daemon may be restarting
+		msg.sub.cancel()
+		delete(r.crashWatches, msg.sub.host)
+		return r, r.retryCrashWatch()
+
+	case watchCrashesMsg:
+		r.crashRetry = false
+		return r, r.watchCrashes()
+	}
+
+	var cmd tea.Cmd
+	r.stack[len(r.stack)-1], cmd = r.top().Update(msg)
+
+	return r, cmd
+}
+
+func (r routerModel) View() string {
+	view := withAlerts(r.top().View(), r.width)
+	if r.help != nil {
+		view = renderHelp(r.help.title, r.help.keys, r.width)
+	}
+	if r.confirm != nil {
+		view = r.confirm.view(r.width)
+	}
+	switch {
+	case r.commanding:
+		view = replaceLastLine(view, r.palette.View()+"  "+paletteHelp(r.palette, r.width-lipgloss.Width(r.palette.View())))
+	case r.result != nil && r.result.err != nil:
+		view = replaceLastLine(view, StatusErrorStyle.Render("✖ "+r.result.err.Error()))
+	case r.result != nil:
+		view = replaceLastLine(view, StatusInfoStyle.Render("✔ "+r.result.message))
+	}
+	if r.configErr != nil {
+		return view + "\n" + StatusErrorStyle.Render("✖ config not reloaded: "+r.configErr.Error())
+	}
+	return view
+}
+
+// updatePalette feeds a key to the open palette: enter runs the command,
+// esc closes it.
+func (r routerModel) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		r.commanding = false
+		r.palette.Blur()
+		return r, nil
+
+	case tea.KeyEnter:
+		r.commanding = false
+		r.palette.Blur()
+		env, ok := r.commandEnv()
+		if !ok {
+			return r, nil
+		}
+		cmd, err := dispatch(env, r.palette.Value())
+		if err != nil {
+			r.result = &commandMsg{err: err}
+		}
+		return r, cmd
+	}
+
+	var cmd tea.Cmd
+	r.palette, cmd = r.palette.Update(msg)
+	return r, cmd
+}
+
+// commandEnv describes the screen on top for commands. They talk to the
+// daemon of the current context, the one of the first screen on the stack,
+// and don't run while a screen is taking text or before Docker is up.
+func (r routerModel) commandEnv() (commandEnv, bool) {
+	if t, ok := r.top().(typingScreen); ok && t.typing() {
+		return commandEnv{}, false
+	}
+	dockerClient := r.dockerClient()
+	if dockerClient == nil {
+		return commandEnv{}, false
+	}
+	return commandEnv{
+		dockerClient: dockerClient,
+		width:        r.width,
+		height:       r.height,
+		top:          r.top(),
+	}, true
+}
+
+// dockerClient is the client of the first screen on the stack that talks to
+// Docker, if any.
+func (r routerModel) dockerClient() Docker {
+	for _, m := range r.stack {
+		if screen, ok := m.(dockerScreen); ok {
+			return screen.client()
+		}
+	}
+	return nil
+}
+
+func (r routerModel) top() tea.Model {
+	return r.stack[len(r.stack)-1]
+}
+
+// broadcast sends msg to every screen on the stack, not just the one on top.
+func (r *routerModel) broadcast(msg tea.Msg) tea.Cmd {
+	cmds := []tea.Cmd{}
+	for i, m := range r.stack {
+		var cmd tea.Cmd
+		r.stack[i], cmd = m.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// sized hands a new screen the current window size before it is shown.
+func (r routerModel) sized(m tea.Model) (tea.Model, tea.Cmd) {
+	if r.width == 0 && r.height == 0 {
+		return m, nil
+	}
+	return m.Update(tea.WindowSizeMsg{Width: r.width, Height: r.height})
+}
+
+// replaceLastLine swaps the bottom line of view, where screens keep their
+// help, for line.
+func replaceLastLine(view string, line string) string {
+	if i := strings.LastIndex(view, "\n"); i >= 0 {
+		return view[:i+1] + line
+	}
+	return line
+}
@@ -0,0 +1,178 @@
+package src
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// containerSort is the order of the containers table. Services are sorted
+// inside their stack the same way, a stack taking the values of its
+// services: its newest creation time, its longest uptime, its total CPU
+// and memory.
+type containerSort struct {
+	Key  string `yaml:"key"`
+	Desc bool   `yaml:"desc"`
+}
+
+// sortKeys are the orders the sort key cycles through.
+var sortKeys = []string{"name", "state", "created", "image", "uptime", "cpu", "mem", "health"}
+
+var defaultSort = containerSort{Key: "name"}
+
+// next moves on to the next key, ascending.
+func (s containerSort) next() containerSort {
+	i := slices.Index(sortKeys, s.Key)
+	return containerSort{Key: sortKeys[(i+1)%len(sortKeys)]}
+}
+
+func (s containerSort) reversed() containerSort {
+	s.Desc = !s.Desc
+	return s
+}
+
+func (s containerSort) String() string {
+	if s.Desc {
+		return s.Key + " ↓"
+	}
+	return s.Key + " ↑"
+}
+
+// needsStats tells whether sorting by s needs CPU and memory figures.
+func (s containerSort) needsStats() bool {
+	return s.Key == "cpu" || s.Key == "mem"
+}
+
+// sortContainers returns containers in the order of s, leaving the list it
+// was given alone. Ties go by name.
+func sortContainers(containers []Container, s containerSort) []Container {
+	sorted := slices.Clone(containers)
+	for i, c := range sorted {
+		if len(c.Children) > 0 {
+			sorted[i].Children = sortContainers(c.Children, s)
+		}
+	}
+
+	compare := sortCompare[s.Key]
+	slices.SortStableFunc(sorted, func(a, b Container) int {
+		n := compare(a, b)
+		if s.Desc {
+			n = -n
+		}
+		if n == 0 {
+			n = strings.Compare(a.Name, b.Name)
+		}
+		return n
+	})
+	return sorted
+}
+
+var sortCompare = map[string]func(a, b Container) int{
+	"name": func(a, b Container) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	"state": func(a, b Container) int {
+		return cmp.Compare(stateRank(a), stateRank(b))
+	},
+	"created": func(a, b Container) int {
+		return cmp.Compare(aggregate(a, created, greatest), aggregate(b, created, greatest))
+	},
+	"image": func(a, b Container) int {
+		return strings.Compare(a.Image, b.Image)
+	},
+	"uptime": func(a, b Container) int {
+		return cmp.Compare(aggregate(a, uptime, greatest), aggregate(b, uptime, greatest))
+	},
+	"cpu": func(a, b Container) int {
+		return cmp.Compare(aggregate(a, func(c Container) float64 { return c.CPU }, sum), aggregate(b, func(c Container) float64 { return c.CPU }, sum))
+	},
+	"mem": func(a, b Container) int {
+		return cmp.Compare(aggregate(a, func(c Container) uint64 { return c.Memory }, sum), aggregate(b, func(c Container) uint64 { return c.Memory }, sum))
+	},
+	"health": func(a, b Container) int {
+		return cmp.Compare(healthRank(a), healthRank(b))
+	},
+}
+
+func sum[T cmp.Ordered](a, b T) T {
+	return a + b
+}
+
+func greatest[T cmp.Ordered](a, b T) T {
+	return max(a, b)
+}
+
+// aggregate is the value of c, or of its services combined with merge for a
+// stack.
+func aggregate[T cmp.Ordered](c Container, value func(Container) T, merge func(T, T) T) T {
+	if c.Type != TypeComposeStack {
+		return value(c)
+	}
+	var total T
+	for _, child := range c.Children {
+		total = merge(total, value(child))
+	}
+	return total
+}
+
+// stateOrder puts the liveliest states first; a stack counts as its
+// liveliest service.
+var stateOrder = []containerTypes.ContainerState{
+	containerTypes.StateRunning,
+	containerTypes.StateRestarting,
+	containerTypes.StatePaused,
+	containerTypes.StateCreated,
+	containerTypes.StateExited,
+	containerTypes.StateDead,
+}
+
+func stateRank(c Container) int {
+	if c.Type == TypeComposeStack {
+		rank := len(stateOrder)
+		for _, child := range c.Children {
+			rank = min(rank, stateRank(child))
+		}
+		return rank
+	}
+	if i := slices.Index(stateOrder, c.State); i >= 0 {
+		return i
+	}
+	return len(stateOrder)
+}
+
+var uptimePattern = regexp.MustCompile(`^Up (Less than a second|About an? (minute|hour)|(\d+) (second|minute|hour|day|week|month|year)s?)`)
+
+var uptimeUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+	"month":  30 * 24 * time.Hour,
+	"year":   365 * 24 * time.Hour,
+}
+
+func created(c Container) int64 {
+	return c.Created.Unix()
+}
+
+// uptime reads how long c has been up from its status, as the daemon words
+// it, e.g. "Up 3 hours (healthy)". Containers that aren't up have none.
+func uptime(c Container) time.Duration {
+	m := uptimePattern.FindStringSubmatch(c.Status)
+	switch {
+	case m == nil:
+		return 0
+	case m[2] != "":
+		return uptimeUnits[m[2]]
+	case m[3] != "":
+		n, _ := strconv.Atoi(m[3])
+		return time.Duration(n) * uptimeUnits[m[4]]
+	}
+	return time.Second / 2
+}
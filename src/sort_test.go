@@ -0,0 +1,107 @@
+package src
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestSortContainers(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	containers := []Container{
+		{Name: "worker", Type: TypeContainer, Image: "busybox:latest", State: container.StateExited, Status: "Exited (1) 5 minutes ago", Created: day},
+		{Name: "nginx", Type: TypeContainer, Image: "nginx:1.27", State: container.StateRunning, Status: "Up 2 hours", Created: day.Add(time.Hour), CPU: 1, Memory: 300},
+		{Name: "shop", Type: TypeComposeStack, Children: []Container{
+			{Name: "shop-db", Type: TypeContainer, Image: "postgres:16", State: container.StateRunning, Status: "Up 3 hours (healthy)", Created: day, CPU: 2, Memory: 100},
+			{Name: "shop-api", Type: TypeContainer, Image: "shop/api:dev", State: container.StatePaused, Status: "Up About a minute (Paused)", Created: day.Add(2 * time.Hour), CPU: 0.5, Memory: 100},
+		}},
+	}
+
+	tests := []struct {
+		sort containerSort
+		want []string
+	}{
+		{containerSort{Key: "name"}, []string{"nginx", "shop", "shop-api", "shop-db", "worker"}},
+		{containerSort{Key: "name", Desc: true}, []string{"worker", "shop", "shop-db", "shop-api", "nginx"}},
+		{containerSort{Key: "state"}, []string{"nginx", "shop", "shop-db", "shop-api", "worker"}},
+		{containerSort{Key: "created", Desc: true}, []string{"shop", "shop-api", "shop-db", "nginx", "worker"}},
+		{containerSort{Key: "image"}, []string{"shop", "shop-db", "shop-api", "worker", "nginx"}},
+		{containerSort{Key: "uptime", Desc: true}, []string{"shop", "shop-db", "shop-api", "nginx", "worker"}},
+		{containerSort{Key: "cpu", Desc: true}, []string{"shop", "shop-db", "shop-api", "nginx", "worker"}},
+		{containerSort{Key: "mem", Desc: true}, []string{"nginx", "shop", "shop-api", "shop-db", "worker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort.String(), func(t *testing.T) {
+			got := []string{}
+			for _, c := range sortContainers(containers, tt.sort) {
+				got = append(got, c.Name)
+				for _, child := range c.Children {
+					got = append(got, child.Name)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("sorted by %s: %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+
+	if containers[0].Name != "worker" || containers[2].Children[0].Name != "shop-db" {
+		t.Error("sortContainers changed the list it was given")
+	}
+}
+
+func TestUptime(t *testing.T) {
+	tests := []struct {
+		status string
+		want   time.Duration
+	}{
+		{"Up 3 hours (healthy)", 3 * time.Hour},
+		{"Up About a minute", time.Minute},
+		{"Up About an hour", time.Hour},
+		{"Up 12 seconds", 12 * time.Second},
+		{"Up 2 weeks (Paused)", 14 * 24 * time.Hour},
+		{"Up Less than a second", time.Second / 2},
+		{"Exited (0) 3 hours ago", 0},
+		{"Created", 0},
+	}
+	for _, tt := range tests {
+		if got := uptime(Container{Status: tt.status}); got != tt.want {
+			t.Errorf("uptime(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+// useState keeps the state in a file of its own for the test.
+func useState(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	SetStatePath(path)
+	t.Cleanup(func() { SetStatePath("") })
+	return path
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := useState(t)
+
+	want := containerSort{Key: "uptime", Desc: true}
+	msg := saveStateCmd(0, func(s *State) { s.ContainersSort = want })()
+	if err := msg.(stateSavedMsg).err; err != nil {
+		t.Fatal(err)
+	}
+
+	SetStatePath(path)
+	if got := CurrentState().ContainersSort; got != want {
+		t.Errorf("sort read back = %v, want %v", got, want)
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		saveStateCmd(0, func(s *State) { s.ContainersSort = containerSort{Key: "color"} })()
+		SetStatePath(path)
+		if got := CurrentState().ContainersSort; got != defaultSort {
+			t.Errorf("unknown sort key read back as %v, want the default", got)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const daemonPollInterval = 2 * time.Second
+
+type daemonConnectMsg struct {
+	dockerClient Docker
+	err          error
+}
+
+type daemonLaunchMsg struct {
+	err error
+}
+
+type daemonRetryMsg struct{}
+
+// Startup Model
+
+type startupModel struct {
+	spinner     spinner.Model
+	contextName string
+	launcher    DaemonLauncher
+	timeout     time.Duration
+	started     time.Time
+	launched    bool
+	status      string
+	err         error
+	width       int
+	height      int
+}
+
+// InitStartupModel waits for the daemon behind contextName, an empty name
+// meaning whatever DOCKER_HOST/DOCKER_CONTEXT or the Docker config select.
+func InitStartupModel(contextName string, launcher DaemonLauncher, timeout time.Duration) startupModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = SpinnerStyle
+
+	return startupModel{
+		spinner:     s,
+		contextName: contextName,
+		launcher:    launcher,
+		timeout:     timeout,
+		started:     time.Now(),
+		status:      "Connecting to Docker daemon...",
+	}
+}
+
+func (s startupModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("StarDocker"), s.spinner.Tick, connectDaemonCmd(s.contextName))
+}
+
+func (s startupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		s.height = msg.Height
+		return s, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return s, tea.Quit
+		}
+
+	case daemonConnectMsg:
+		if msg.err == nil {
+			return s, reset(nil, defaultStack(msg.dockerClient, s.width, s.height)...)
+		}
+
+		if time.Since(s.started) >= s.timeout {
+			s.err = fmt.Errorf("Docker daemon did not become ready within %s (launcher: %s): %w", s.timeout, s.launcher.Name(), msg.err)
+			return s, nil
+		}
+
+		if !s.launched {
+			s.launched = true
+			s.status = fmt.Sprintf("Docker daemon not running, starting it with the %s launcher...", s.launcher.Name())
+			return s, launchDaemonCmd(s.launcher, s.timeout)
+		}
+
+		return s, retryDaemonCmd()
+
+	case daemonLaunchMsg:
+		if msg.err != nil {
+			s.err = fmt.Errorf("could not start Docker daemon (launcher: %s): %w", s.launcher.Name(), msg.err)
+			return s, nil
+		}
+		s.status = "Waiting for Docker daemon..."
+		return s, retryDaemonCmd()
+
+	case daemonRetryMsg:
+		return s, connectDaemonCmd(s.contextName)
+
+	case spinner.TickMsg:
+		if s.err != nil {
+			return s, nil
+		}
+		var cmd tea.Cmd
+		s.spinner, cmd = s.spinner.Update(msg)
+		return s, cmd
+	}
+
+	return s, nil
+}
+
+func (s startupModel) View() string {
+	doc := strings.Builder{}
+
+	doc.WriteString("\n\n")
+
+	if s.err != nil {
+		doc.WriteString(HelpStyle.Render(s.err.Error()) + "\n\n")
+		doc.WriteString(HelpStyle.Render("Start Docker manually or pick another launcher with --launcher. Press q to quit."))
+		return doc.String()
+	}
+
+	elapsed := time.Since(s.started).Truncate(time.Second)
+	doc.WriteString(HelpStyle.Render(fmt.Sprintf("%s %s (%s / %s)", s.spinner.View(), s.status, elapsed, s.timeout)))
+
+	return doc.String()
+}
+
+// defaultStack is what is shown once connected: the menu, with the
+// default_view from the config opened on top of it.
+func defaultStack(dockerClient Docker, width int, height int) []tea.Model {
+	stack := []tea.Model{InitIndexModel(dockerClient)}
+
+	switch CurrentConfig().DefaultView {
+	case "containers":
+		stack = append(stack, InitListContainersModel(dockerClient, width, height))
+	case "images":
+		stack = append(stack, InitListImagesModel(dockerClient, width, height))
+	case "networks":
+		stack = append(stack, InitListNetworksModel(dockerClient, width, height))
+	}
+
+	return stack
+}
+
+func connectDaemonCmd(contextName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(rootCtx, daemonPollInterval)
+		defer cancel()
+
+		dockerClient, err := NewDockerClient(ctx, contextName)
+		return daemonConnectMsg{dockerClient: dockerClient, err: err}
+	}
+}
+
+func launchDaemonCmd(launcher DaemonLauncher, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(rootCtx, timeout)
+		defer cancel()
+
+		return daemonLaunchMsg{err: launcher.Launch(ctx)}
+	}
+}
+
+func retryDaemonCmd() tea.Cmd {
+	return tea.Tick(daemonPollInterval, func(time.Time) tea.Msg {
+		return daemonRetryMsg{}
+	})
+}
@@ -0,0 +1,97 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// State is what stardocker remembers from one session to the next, kept
+// apart from the config since it is written by the program, not the user.
+type State struct {
+	ContainersSort containerSort `yaml:"containers_sort"`
+}
+
+func defaultState() State {
+	return State{ContainersSort: defaultSort}
+}
+
+var (
+	stateMu      sync.Mutex
+	currentState = defaultState()
+	statePath    string
+)
+
+// DefaultStatePath is $XDG_STATE_HOME/stardocker/state.yaml, falling back
+// to ~/.local/state.
+func DefaultStatePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "stardocker", "state.yaml")
+}
+
+// SetStatePath reads the state kept at path and saves it there from now
+// on. A missing or unreadable file starts over from the defaults.
+func SetStatePath(path string) {
+	s := defaultState()
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &s); err != nil {
+			s = defaultState()
+		}
+	}
+	if !slices.Contains(sortKeys, s.ContainersSort.Key) {
+		s.ContainersSort = defaultSort
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	statePath = path
+	currentState = s
+}
+
+func CurrentState() State {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	return currentState
+}
+
+// stateSavedMsg reports a failure to save the state.
+type stateSavedMsg struct {
+	viewID int64
+	err    error
+}
+
+// saveStateCmd changes the state with update and writes it out.
+func saveStateCmd(viewID int64, update func(s *State)) tea.Cmd {
+	stateMu.Lock()
+	update(&currentState)
+	s, path := currentState, statePath
+	stateMu.Unlock()
+
+	if path == "" {
+		return nil
+	}
+
+	return func() tea.Msg {
+		data, err := yaml.Marshal(s)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+		return stateSavedMsg{viewID: viewID, err: err}
+	}
+}
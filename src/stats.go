@@ -0,0 +1,85 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// FetchStats fills in CPU and memory use of the running containers, stack
+// services included, asking every container at the same time. Containers
+// whose stats can't be had are left without.
+func FetchStats(ctx context.Context, dockerClient Docker, containers []Container) {
+	var wg sync.WaitGroup
+	for i := range containers {
+		c := &containers[i]
+		if c.Type == TypeComposeStack {
+			FetchStats(ctx, dockerClient, c.Children)
+			continue
+		}
+		if c.State != containerTypes.StateRunning {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := hostClient(dockerClient, c.Host)
+			if err != nil {
+				return
+			}
+			stats, err := ContainerStats(ctx, client, c.ID)
+			if err != nil {
+				return
+			}
+			c.CPU, c.Memory, c.HasStats = cpuPercent(stats), memoryUsage(stats), true
+		}()
+	}
+	wg.Wait()
+}
+
+// ContainerStats takes a single sample of the resource use of a container.
+func ContainerStats(ctx context.Context, dockerClient Docker, containerID string) (containerTypes.StatsResponse, error) {
+	var stats containerTypes.StatsResponse
+	err := retry(ctx, func() error {
+		// Without streaming the daemon waits for a second sample, so the
+		// previous CPU figures are there to work out a rate
+		response, err := dockerClient.ContainerStats(ctx, containerID, false)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		return json.NewDecoder(response.Body).Decode(&stats)
+	})
+	return stats, err
+}
+
+// cpuPercent works out CPU use the way docker stats does: 100% is one
+// core.
+func cpuPercent(stats containerTypes.StatsResponse) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * cpus * 100
+}
+
+// memoryUsage leaves the page cache out, like docker stats.
+func memoryUsage(stats containerTypes.StatsResponse) uint64 {
+	usage := stats.MemoryStats.Usage
+	cache := stats.MemoryStats.Stats["inactive_file"]
+	if cache == 0 {
+		cache = stats.MemoryStats.Stats["total_inactive_file"]
+	}
+	if cache < usage {
+		return usage - cache
+	}
+	return usage
+}
@@ -0,0 +1,111 @@
+package src
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const toastDuration = 5 * time.Second
+
+// statusBar is the line under every screen reporting how Docker calls went.
+// Errors stay until the next successful action replaces them, info messages
+// fade after toastDuration. Losing the connection flips it into an
+// unreachable state that is only left once the daemon answers a ping. While
+// a call is in flight a spinner is shown in front of the message.
+type statusBar struct {
+	spinner     spinner.Model
+	loading     bool
+	message     string
+	isError     bool
+	until       time.Time
+	unreachable bool
+}
+
+func newStatusBar() statusBar {
+	s := spinner.New()
+	s.Spinner = spinner.MiniDot
+
+	return statusBar{spinner: s}
+}
+
+func (s *statusBar) Info(message string) {
+	s.message = message
+	s.isError = false
+	s.until = time.Now().Add(toastDuration)
+}
+
+func (s *statusBar) Error(err error) {
+	s.message = err.Error()
+	s.isError = true
+	s.until = time.Time{}
+	if isDaemonUnreachable(err) {
+		s.unreachable = true
+	}
+}
+
+// Unreachable reports whether the daemon should be pinged before trying any
+// other call.
+func (s statusBar) Unreachable() bool {
+	return s.unreachable
+}
+
+// Recovered leaves the unreachable state after a successful ping.
+func (s *statusBar) Recovered() {
+	s.unreachable = false
+	s.Info("Docker daemon is reachable again")
+}
+
+// StartLoading shows the spinner, returning the command that animates it.
+func (s *statusBar) StartLoading() tea.Cmd {
+	if s.loading {
+		return nil
+	}
+	s.loading = true
+	return s.spinner.Tick
+}
+
+// Tick animates a spinner that was started before the screen was shown.
+func (s statusBar) Tick() tea.Cmd {
+	if !s.loading {
+		return nil
+	}
+	return s.spinner.Tick
+}
+
+func (s *statusBar) StopLoading() {
+	s.loading = false
+}
+
+func (s statusBar) Update(msg tea.Msg) (statusBar, tea.Cmd) {
+	// Let the spinner's tick chain die out once nothing is loading
+	if !s.loading {
+		return s, nil
+	}
+
+	var cmd tea.Cmd
+	s.spinner, cmd = s.spinner.Update(msg)
+	return s, cmd
+}
+
+func (s statusBar) View() string {
+	prefix := ""
+	if s.loading {
+		prefix = SpinnerStyle.Render(s.spinner.View()) + " "
+	}
+
+	if s.unreachable {
+		return StatusErrorStyle.Render(prefix + "⚠ Docker daemon unreachable, retrying... " + s.message)
+	}
+	if s.message == "" || (!s.isError && time.Now().After(s.until)) {
+		if s.loading {
+			return HelpStyle.Render(prefix + "Loading...")
+		}
+		return ""
+	}
+	if s.isError {
+		return StatusErrorStyle.Render(prefix + "✖ " + s.message)
+	}
+	return StatusInfoStyle.Render(prefix + "✔ " + s.message)
+}
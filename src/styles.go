@@ -1,38 +1,120 @@
 package src
 
-import "github.com/charmbracelet/lipgloss"
-
-// Index Styles
-var TitleStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#020202ff")).
-	Background(lipgloss.Color("#f9a318ff")).
-	MarginLeft(5).
-	Padding(2, 3, 0, 0)
-
-var ContentStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#020202ff")).
-	Background(lipgloss.Color("#6bc6ffff")).
-	Padding(1, 3, 0, 2).
-	Width(30).Height(20).
-	MarginLeft(5).
-	Bold(true)
-
-var HelpStyle = lipgloss.NewStyle().
-	MarginLeft(5)
-
-// Containers Styles
-var ContainerTitleStyle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(lipgloss.Color("#020202ff")).
-	Background(lipgloss.Color("#f9a318ff")).
-	MarginLeft(1).
-	Padding(1).
-	Width(70)
-
-var ContainerContentStyle = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(lipgloss.Color("#020202ff")).
-	Background(lipgloss.Color("#6bc6ffff")).
-	Padding(1).
-	Width(70).
-	MarginLeft(1)
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The styles below are built from the active Theme by applyTheme; screens
+// pick up a new theme the next time they render.
+var (
+	theme Theme
+
+	// Index Styles
+	TitleStyle     lipgloss.Style
+	ContentStyle   lipgloss.Style
+	HelpStyle      lipgloss.Style
+	MenuTableStyle lipgloss.Style
+
+	// Containers Styles
+	ContainerTitleStyle   lipgloss.Style
+	ContainerContentStyle lipgloss.Style
+	TableStyle            lipgloss.Style
+
+	// DialogStyle frames dialogs such as the summary of a bulk action
+	DialogStyle lipgloss.Style
+
+	// Status Bar Styles
+	StatusErrorStyle lipgloss.Style
+	StatusInfoStyle  lipgloss.Style
+
+	// SpinnerStyle colors every spinner
+	SpinnerStyle lipgloss.Style
+)
+
+func init() {
+	applyTheme(builtinThemes[ThemeDark])
+}
+
+func applyTheme(t Theme) {
+	theme = t
+
+	TitleStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Text)).
+		Background(lipgloss.Color(t.Accent)).
+		MarginLeft(5).
+		Padding(2, 3, 0, 0)
+
+	ContentStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Text)).
+		Background(lipgloss.Color(t.Secondary)).
+		Padding(1, 3, 0, 2).
+		Width(30).Height(20).
+		MarginLeft(5).
+		Bold(true)
+
+	HelpStyle = lipgloss.NewStyle().
+		MarginLeft(5)
+
+	MenuTableStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color(t.Border)).
+		MarginLeft(4)
+
+	ContainerTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(t.Text)).
+		Background(lipgloss.Color(t.Accent)).
+		MarginLeft(1).
+		Padding(1).
+		Width(70)
+
+	ContainerContentStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(t.Text)).
+		Background(lipgloss.Color(t.Secondary)).
+		Padding(1).
+		Width(70).
+		MarginLeft(1)
+
+	TableStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color(t.Border)).
+		MarginLeft(1)
+
+	DialogStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(t.Accent)).
+		Padding(1, 2).
+		MarginLeft(1)
+
+	StatusErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Error)).
+		MarginLeft(5)
+
+	StatusInfoStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Success)).
+		MarginLeft(5)
+
+	SpinnerStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Accent))
+}
+
+// tableStyles styles the header and cursor of a table. Without colors the
+// cursor is shown in reverse video.
+func tableStyles() table.Styles {
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color(theme.Border)).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color(theme.SelectedText)).
+		Background(lipgloss.Color(theme.SelectedBackground)).
+		Bold(false)
+	if theme.SelectedBackground == "" {
+		s.Selected = s.Selected.Reverse(true)
+	}
+	return s
+}
@@ -0,0 +1,187 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is every color the UI uses, as hex values or ANSI numbers. An empty
+// color means none.
+type Theme struct {
+	// Accent is the background of titles and the color of spinners.
+	Accent    string `yaml:"accent"`
+	Secondary string `yaml:"secondary"`
+	// Text is written on top of Accent and Secondary.
+	Text               string `yaml:"text"`
+	Error              string `yaml:"error"`
+	Success            string `yaml:"success"`
+	Warning            string `yaml:"warning"`
+	Border             string `yaml:"border"`
+	SelectedText       string `yaml:"selected_text"`
+	SelectedBackground string `yaml:"selected_background"`
+}
+
+// UserTheme is a theme defined in the themes section of the config. Colors
+// it leaves out come from Base.
+type UserTheme struct {
+	Base  string `yaml:"base"`
+	Theme `yaml:",inline"`
+}
+
+const (
+	ThemeAuto = "auto"
+	ThemeDark = "dark"
+	// themeNoColor is forced by NO_COLOR
+	themeNoColor = "no-color"
+)
+
+var builtinThemes = map[string]Theme{
+	ThemeDark: {
+		Accent:             "#f9a318ff",
+		Secondary:          "#6bc6ffff",
+		Text:               "#020202ff",
+		Error:              "#ff5f5fff",
+		Success:            "#5fd75fff",
+		Warning:            "#ffd75fff",
+		Border:             "240",
+		SelectedText:       "229",
+		SelectedBackground: "57",
+	},
+	"light": {
+		Accent:             "#d97706ff",
+		Secondary:          "#0284c7ff",
+		Text:               "#ffffffff",
+		Error:              "#c62828ff",
+		Success:            "#2e7d32ff",
+		Warning:            "#b26a00ff",
+		Border:             "245",
+		SelectedText:       "#ffffffff",
+		SelectedBackground: "63",
+	},
+	"high-contrast": {
+		Accent:             "#ffff00ff",
+		Secondary:          "#00ffffff",
+		Text:               "#000000ff",
+		Error:              "#ff0000ff",
+		Success:            "#00ff00ff",
+		Warning:            "#ffff00ff",
+		Border:             "#ffffffff",
+		SelectedText:       "#000000ff",
+		SelectedBackground: "#ffffffff",
+	},
+	themeNoColor: {},
+}
+
+// BuiltinThemeNames lists the themes that are always there.
+func BuiltinThemeNames() []string {
+	return []string{ThemeAuto, ThemeDark, "light", "high-contrast"}
+}
+
+// noColor honours https://no-color.org.
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// SetupTerminal prepares color output before the UI starts, while the
+// terminal can still be asked about its background. With NO_COLOR lipgloss
+// would drop bold and reverse video too, so on a terminal those are kept
+// to show the cursor and titles.
+func SetupTerminal() {
+	lipgloss.HasDarkBackground()
+
+	if noColor() && termenv.NewOutput(os.Stdout).ColorProfile() != termenv.Ascii {
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+}
+
+// resolveTheme works out the theme c asks for: auto picks dark or light
+// after the terminal background, and single colors from theme.colors go on
+// top.
+func resolveTheme(c Config) Theme {
+	if noColor() {
+		return builtinThemes[themeNoColor]
+	}
+
+	name := c.Theme.Name
+	if name == ThemeAuto {
+		name = ThemeDark
+		if !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+
+	t, ok := builtinThemes[name]
+	if !ok {
+		user := c.Themes[name]
+		base := user.Base
+		if base == "" {
+			base = ThemeDark
+		}
+		t = overlayTheme(builtinThemes[base], user.Theme)
+	}
+
+	return overlayTheme(t, c.Theme.Colors)
+}
+
+// overlayTheme replaces the colors of t that are set in over.
+func overlayTheme(t Theme, over Theme) Theme {
+	tv := reflect.ValueOf(&t).Elem()
+	ov := reflect.ValueOf(over)
+	for i := range tv.NumField() {
+		if color := ov.Field(i).String(); color != "" {
+			tv.Field(i).SetString(color)
+		}
+	}
+	return t
+}
+
+// themeColors lists the colors of t by their config name.
+func themeColors(t Theme) map[string]string {
+	colors := map[string]string{}
+	tt := reflect.TypeOf(t)
+	tv := reflect.ValueOf(t)
+	for i := range tt.NumField() {
+		colors[tt.Field(i).Tag.Get("yaml")] = tv.Field(i).String()
+	}
+	return colors
+}
+
+// validateThemes checks the theme and themes sections of c.
+func validateThemes(c Config) []error {
+	errs := []error{}
+
+	if _, ok := c.Themes[c.Theme.Name]; !ok && !slices.Contains(BuiltinThemeNames(), c.Theme.Name) {
+		errs = append(errs, fmt.Errorf("theme.name: %q is neither one of %v nor defined under themes", c.Theme.Name, BuiltinThemeNames()))
+	}
+	errs = append(errs, validateColors("theme.colors", c.Theme.Colors)...)
+
+	for name, t := range c.Themes {
+		if slices.Contains(BuiltinThemeNames(), name) {
+			errs = append(errs, fmt.Errorf("themes.%s: the name is taken by a built-in theme", name))
+		}
+		if _, ok := builtinThemes[t.Base]; t.Base != "" && (!ok || t.Base == themeNoColor) {
+			errs = append(errs, fmt.Errorf("themes.%s.base: %q is not a built-in theme", name, t.Base))
+		}
+		errs = append(errs, validateColors("themes."+name, t.Theme)...)
+	}
+
+	return errs
+}
+
+func validateColors(section string, t Theme) []error {
+	errs := []error{}
+	for name, color := range themeColors(t) {
+		if color != "" && !validColor(color) {
+			errs = append(errs, fmt.Errorf("%s.%s: %q is neither a hex color nor an ANSI color number", section, name, color))
+		}
+	}
+	// Map order is random, keep the messages stable
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errs
+}
@@ -0,0 +1,367 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	containerTypes "github.com/docker/docker/api/types/container"
+)
+
+// Topology Model
+
+type topologyModel struct {
+	keys         scrollKeys
+	dockerClient Docker
+	viewID       int64
+	width        int
+	height       int
+	viewport     viewport.Model
+	loading      bool
+	status       statusBar
+}
+
+// TopologyContainer is the part of a container the topology map cares about.
+type TopologyContainer struct {
+	ID             string
+	Name           string
+	ComposeProject string
+	NetworkMode    string
+}
+
+func InitTopologyModel(dockerClient Docker, width int, height int) topologyModel {
+	t := topologyModel{
+		keys:         newScrollKeys("topology"),
+		dockerClient: dockerClient,
+		viewID:       nextViewID(),
+		width:        width,
+		height:       height,
+		viewport:     viewport.New(width, max(0, height-5)),
+		status:       newStatusBar(),
+	}
+	t.viewport.KeyMap = t.keys.viewportKeyMap()
+	t.loading = true
+	t.status.StartLoading()
+
+	return t
+}
+
+type topologyMsg struct {
+	viewID     int64
+	networks   []Network
+	containers []TopologyContainer
+	err        error
+}
+
+func (t topologyModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Network Topology"), t.status.Tick(), t.fetchCmd(), tickCmd(t.viewID))
+}
+
+func (t topologyModel) client() Docker {
+	return t.dockerClient
+}
+
+func (t topologyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		t.keys = newScrollKeys("topology")
+		t.viewport.KeyMap = t.keys.viewportKeyMap()
+		return t, nil
+
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+		t.viewport.Width = msg.Width
+		t.viewport.Height = max(0, msg.Height-5)
+		return t, nil
+
+	case tea.KeyMsg:
+		switch {
+
+		case key.Matches(msg, t.keys.Help):
+			return t, showHelp("network topology", t.keys)
+
+		case key.Matches(msg, t.keys.Back):
+			return t, pop()
+
+		case key.Matches(msg, t.keys.Quit):
+			return t, tea.Quit
+
+		case key.Matches(msg, t.keys.Top):
+			t.viewport.GotoTop()
+			return t, nil
+
+		case key.Matches(msg, t.keys.Bottom):
+			t.viewport.GotoBottom()
+			return t, nil
+		}
+
+	case topologyMsg:
+		if msg.viewID != t.viewID {
+			return t, nil
+		}
+		t.loading = false
+		t.status.StopLoading()
+		if msg.err != nil {
+			t.status.Error(msg.err)
+			return t, nil
+		}
+		t.viewport.SetContent(RenderTopology(msg.networks, msg.containers))
+		return t, nil
+
+	case pingMsg:
+		if msg.viewID != t.viewID || msg.err != nil {
+			return t, nil
+		}
+		t.status.Recovered()
+		cmd = t.refresh()
+		return t, cmd
+
+	case spinner.TickMsg:
+		t.status, cmd = t.status.Update(msg)
+		return t, cmd
+
+	case tickMsg:
+		if msg.viewID != t.viewID {
+			return t, nil
+		}
+		cmd = t.refresh()
+		return t, tea.Batch(cmd, tickCmd(t.viewID))
+	}
+
+	t.viewport, cmd = t.viewport.Update(msg)
+
+	return t, cmd
+}
+
+// refresh redraws the map from fresh data unless a request is already on
+// its way. While the daemon is unreachable it only pings it.
+func (t *topologyModel) refresh() tea.Cmd {
+	if t.status.Unreachable() {
+		return pingCmd(t.viewID, t.dockerClient)
+	}
+	if t.loading {
+		return nil
+	}
+	t.loading = true
+	return t.fetchCmd()
+}
+
+func (t topologyModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient := t.viewID, t.dockerClient
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		networks, err := FetchNetworks(ctx, dockerClient)
+		if err != nil {
+			return topologyMsg{viewID: viewID, err: err}
+		}
+		containers, err := FetchTopologyContainers(ctx, dockerClient)
+		return topologyMsg{viewID: viewID, networks: networks, containers: containers, err: err}
+	}
+}
+
+func (t topologyModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(t.width, lipgloss.Left, ContainerTitleStyle.Render("NETWORK TOPOLOGY"))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n")
+
+	doc.WriteString(t.viewport.View() + "\n")
+
+	doc.WriteString(t.status.View() + "\n")
+
+	doc.WriteString(helpLine(t.keys))
+
+	return doc.String()
+}
+
+func FetchTopologyContainers(ctx context.Context, dockerClient Docker) ([]TopologyContainer, error) {
+	var containers []containerTypes.Summary
+	err := retry(ctx, func() (err error) {
+		containers, err = dockerClient.ContainerList(ctx, containerTypes.ListOptions{All: true})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	topologyContainers := make([]TopologyContainer, 0, len(containers))
+	for _, container := range containers {
+		topologyContainers = append(topologyContainers, TopologyContainer{
+			ID:             container.ID,
+			Name:           containerName(container),
+			ComposeProject: container.Labels[composeStackIdentifier],
+			NetworkMode:    container.HostConfig.NetworkMode,
+		})
+	}
+
+	return topologyContainers, nil
+}
+
+// RenderTopology draws every network as a node with its attached containers
+// underneath, grouped by compose stack. Containers sharing another
+// container's network namespace are listed separately since they do not
+// appear on any network themselves.
+func RenderTopology(networks []Network, containers []TopologyContainer) string {
+	byName := make(map[string]TopologyContainer)
+	byID := make(map[string]TopologyContainer)
+	for _, container := range containers {
+		byName[container.Name] = container
+		byID[container.ID] = container
+	}
+
+	// Work out which networks each container is attached to, so that
+	// containers bridging several networks can be called out
+	memberships := make(map[string][]string)
+	for _, network := range networks {
+		for _, endpoint := range network.Containers {
+			memberships[endpoint.Name] = append(memberships[endpoint.Name], network.Name)
+		}
+	}
+
+	// Host mode containers don't always show up as endpoints of the host
+	// network, so add any that are missing, to a copy of the caller's
+	// networks
+	networks = slices.Clone(networks)
+	for i, network := range networks {
+		if network.Name != "host" {
+			continue
+		}
+		networks[i].Containers = slices.Clone(network.Containers)
+		for _, container := range containers {
+			if container.NetworkMode != "host" || slices.Contains(memberships[container.Name], "host") {
+				continue
+			}
+			networks[i].Containers = append(networks[i].Containers, NetworkEndpoint{ContainerID: shortID(container.ID), Name: container.Name})
+			memberships[container.Name] = append(memberships[container.Name], "host")
+		}
+	}
+
+	doc := strings.Builder{}
+
+	for _, network := range networks {
+		header := fmt.Sprintf("◉ %s", network.Name)
+		details := []string{network.Driver}
+		if network.Subnet != "" {
+			details = append(details, network.Subnet)
+		}
+		if network.Internal {
+			details = append(details, "internal")
+		}
+		doc.WriteString(fmt.Sprintf("%s (%s)\n", header, strings.Join(details, ", ")))
+
+		// Group the endpoints by compose stack; standalone containers sit
+		// under the empty key
+		stacks := make(map[string][]NetworkEndpoint)
+		for _, endpoint := range network.Containers {
+			project := byName[endpoint.Name].ComposeProject
+			stacks[project] = append(stacks[project], endpoint)
+		}
+
+		stackNames := make([]string, 0, len(stacks))
+		for name := range stacks {
+			if name != "" {
+				stackNames = append(stackNames, name)
+			}
+		}
+		sort.Strings(stackNames)
+
+		type branch struct {
+			label    string
+			children []NetworkEndpoint
+		}
+		branches := []branch{}
+		for _, name := range stackNames {
+			branches = append(branches, branch{label: "▣ " + name, children: stacks[name]})
+		}
+		for _, endpoint := range stacks[""] {
+			branches = append(branches, branch{children: []NetworkEndpoint{endpoint}})
+		}
+
+		if len(branches) == 0 {
+			doc.WriteString("  └── (no containers)\n\n")
+			continue
+		}
+
+		for i, b := range branches {
+			last := i == len(branches)-1
+			connector, indent := "├── ", "│   "
+			if last {
+				connector, indent = "└── ", "    "
+			}
+
+			if b.label == "" {
+				doc.WriteString("  " + connector + topologyEndpoint(b.children[0], network.Name, memberships) + "\n")
+				continue
+			}
+
+			doc.WriteString("  " + connector + b.label + "\n")
+			for j, endpoint := range b.children {
+				childConnector := "├── "
+				if j == len(b.children)-1 {
+					childConnector = "└── "
+				}
+				doc.WriteString("  " + indent + childConnector + topologyEndpoint(endpoint, network.Name, memberships) + "\n")
+			}
+		}
+
+		doc.WriteString("\n")
+	}
+
+	// Containers using another container's network namespace
+	shared := []string{}
+	for _, container := range containers {
+		target, ok := strings.CutPrefix(container.NetworkMode, "container:")
+		if !ok {
+			continue
+		}
+		targetName := target
+		if c, ok := byID[target]; ok {
+			targetName = c.Name
+		} else if c, ok := byName[target]; ok {
+			targetName = c.Name
+		}
+		shared = append(shared, fmt.Sprintf("  %s ──▶ %s", container.Name, targetName))
+	}
+	if len(shared) > 0 {
+		sort.Strings(shared)
+		doc.WriteString("◉ shared network namespaces\n")
+		doc.WriteString(strings.Join(shared, "\n") + "\n")
+	}
+
+	return doc.String()
+}
+
+func topologyEndpoint(endpoint NetworkEndpoint, networkName string, memberships map[string][]string) string {
+	s := endpoint.Name
+	if endpoint.IPv4Address != "" {
+		s += "  " + endpoint.IPv4Address
+	}
+
+	others := []string{}
+	for _, name := range memberships[endpoint.Name] {
+		if name != networkName {
+			others = append(others, name)
+		}
+	}
+	if len(others) > 0 {
+		s += "  ⇄ " + strings.Join(others, ", ")
+	}
+
+	return s
+}
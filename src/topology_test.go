@@ -0,0 +1,43 @@
+package src
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestRenderTopologyLeavesNetworksAlone(t *testing.T) {
+	networks := []Network{
+		{Name: "host", Driver: "host", Containers: make([]NetworkEndpoint, 0, 4)},
+		{Name: "bridge", Driver: "bridge", Containers: []NetworkEndpoint{{ContainerID: "6f1c2b7d9e0a", Name: "nginx"}}},
+	}
+	containers := []TopologyContainer{
+		{ID: "6f1c2b7d9e0a4c3b8a7d", Name: "nginx", NetworkMode: "bridge"},
+		{ID: "0b9e8d7c6a5f4e3d2c1b", Name: "agent", NetworkMode: "host"},
+	}
+
+	view := RenderTopology(networks, containers)
+	if !strings.Contains(view, "agent") {
+		t.Errorf("host mode container missing from the topology:\n%s", view)
+	}
+	if len(networks[0].Containers) != 0 || len(networks[0].Containers[:1]) != 1 || networks[0].Containers[:1][0].Name != "" {
+		t.Errorf("host network of the caller was changed: %+v", networks[0].Containers[:1])
+	}
+	if networks[0].Name != "host" || networks[1].Name != "bridge" {
+		t.Errorf("networks of the caller were reordered: %s, %s", networks[0].Name, networks[1].Name)
+	}
+}
+
+func TestFetchTopologyContainersWithoutNames(t *testing.T) {
+	f := newFakeDocker()
+	f.containers = append(f.containers, container.Summary{ID: "5d4c3b2a1f0e9d8c7b6a", State: container.StateCreated})
+
+	containers, err := FetchTopologyContainers(t.Context(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 1 || containers[0].Name != "5d4c3b2a1f0e9d8c7b6a" {
+		t.Errorf("containers = %+v, want the nameless one going by its ID", containers)
+	}
+}
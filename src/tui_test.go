@@ -0,0 +1,740 @@
+package src
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const (
+	testWidth  = 160
+	testHeight = 40
+
+	// settle is how long a command gets to produce its message. Anything
+	// slower is a timer (refresh ticks, spinners) and is dropped, which
+	// keeps the screens still while they're being compared.
+	settle = 30 * time.Millisecond
+)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+	// Indicators follow the terminal and locale unless the config says
+	os.Setenv("TERM", "xterm-256color")
+	os.Setenv("LC_ALL", "C.UTF-8")
+	os.Exit(m.Run())
+}
+
+// tui drives a model the way the bubbletea runtime would, without a
+// terminal: messages go through Update and the commands that come back are
+// run until only timers are left.
+type tui struct {
+	t     *testing.T
+	model tea.Model
+	quit  bool
+}
+
+func newTUI(t *testing.T, m tea.Model) *tui {
+	t.Helper()
+
+	u := &tui{t: t, model: InitRouterModel(m)}
+	u.run(u.model.Init())
+	u.send(tea.WindowSizeMsg{Width: testWidth, Height: testHeight})
+	return u
+}
+
+func (u *tui) send(msg tea.Msg) {
+	var cmd tea.Cmd
+	u.model, cmd = u.model.Update(msg)
+	u.run(cmd)
+}
+
+// keys types each key in turn, e.g. u.keys("down", "down", "enter").
+func (u *tui) keys(keys ...string) {
+	for _, k := range keys {
+		u.send(keyMsg(k))
+	}
+}
+
+func (u *tui) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	var msg tea.Msg
+	select {
+	case msg = <-done:
+	case <-time.After(settle):
+		return
+	}
+
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			u.run(cmd)
+		}
+	case tea.QuitMsg:
+		u.quit = true
+	default:
+		u.send(msg)
+	}
+}
+
+// golden compares the current screen with testdata/<test name>.golden.
+// Run the tests with -update to accept a change.
+func (u *tui) golden() {
+	u.t.Helper()
+
+	golden(u.t, u.model.View())
+}
+
+// golden compares got with testdata/<test name>.golden.
+func golden(t *testing.T, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", t.Name()+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(want) != got {
+		t.Errorf("output does not match %s (run with -update to accept it)\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// testDocker is a small daemon: two standalone containers and a compose
+// project with two services.
+func testDocker() *fakeDocker {
+	f := newFakeDocker()
+	f.addContainer("6f1c2b7d9e0a4c3b8a7d", "nginx", "nginx:1.27", container.StateRunning, "Up 2 hours", "")
+	f.addContainer("0b9e8d7c6a5f4e3d2c1b", "worker", "busybox:latest", container.StateExited, "Exited (1) 5 minutes ago", "")
+	f.addContainer("a1b2c3d4e5f60718293a", "shop-api", "shop/api:dev", container.StateRunning, "Up 3 hours", "shop")
+	f.addContainer("f0e1d2c3b4a596877869", "shop-db", "postgres:16", container.StateRunning, "Up 3 hours", "shop")
+	f.setLogs("6f1c2b7d9e0a4c3b8a7d", "2025-01-01T10:00:00Z GET / 200\n2025-01-01T10:00:01Z GET /health 200\n")
+	f.addImage("sha256:1111", "nginx:1.27")
+	f.addVolume("shop_pgdata", "local")
+	return f
+}
+
+func TestIndex(t *testing.T) {
+	u := newTUI(t, InitIndexModel(testDocker()))
+	u.golden()
+
+	t.Run("containers", func(t *testing.T) {
+		u.t = t
+		u.keys("enter")
+		u.golden()
+	})
+
+	t.Run("back", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", "down", "down")
+		u.golden()
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		u.t = t
+		u.keys("down", "down", "enter")
+		if !u.quit {
+			t.Error("choosing Exit did not quit")
+		}
+	})
+}
+
+func TestListContainers(t *testing.T) {
+	f := testDocker()
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.golden()
+
+	t.Run("expand stack", func(t *testing.T) {
+		u.t = t
+		u.keys("down", "enter")
+		u.golden()
+	})
+
+	t.Run("collapse stack", func(t *testing.T) {
+		u.t = t
+		u.keys("enter")
+		u.golden()
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		u.t = t
+		u.keys("up", "r")
+		u.golden()
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		u.t = t
+		u.keys("y")
+		u.golden()
+
+		containers, _ := f.ContainerList(t.Context(), container.ListOptions{})
+		for _, c := range containers {
+			if c.ID == "6f1c2b7d9e0a4c3b8a7d" {
+				t.Error("nginx is still running")
+			}
+		}
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		u.t = t
+		u.keys("q")
+		if !u.quit {
+			t.Error("q did not quit")
+		}
+	})
+}
+
+func TestHelp(t *testing.T) {
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+	u.keys("?")
+	u.golden()
+
+	t.Run("close", func(t *testing.T) {
+		u.t = t
+		// The key that closes the overlay does nothing else
+		u.keys("q")
+		if u.quit {
+			t.Error("q quit instead of closing the help")
+		}
+		u.golden()
+	})
+}
+
+func TestPalette(t *testing.T) {
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+	u.keys(":", "lo")
+	u.golden()
+
+	t.Run("logs", func(t *testing.T) {
+		u.t = t
+		u.keys("gs nginx", "enter")
+		if _, ok := u.model.(routerModel).top().(logsModel); !ok {
+			t.Fatal(":logs nginx did not open the logs")
+		}
+		u.golden()
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", ":", "nope", "enter")
+		u.golden()
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		u.t = t
+		u.keys(":", "filter label="+composeStackIdentifier+"=shop", "enter")
+		u.golden()
+	})
+
+	t.Run("volumes", func(t *testing.T) {
+		u.t = t
+		u.keys(":", "volumes", "enter")
+		u.golden()
+	})
+
+	t.Run("context", func(t *testing.T) {
+		u.t = t
+		u.keys(":", "context", "enter")
+		u.golden()
+	})
+}
+
+func TestResetClosesOldClient(t *testing.T) {
+	old, f := testDocker(), testDocker()
+	u := newTUI(t, InitListContainersModel(old, testWidth, testHeight))
+
+	// The screens on the stack may still be using it until the reset lands
+	msg := reset(old, InitIndexModel(f), InitListContainersModel(f, testWidth, testHeight))()
+	if old.isClosed() {
+		t.Fatal("the old client was closed before the router reset the stack")
+	}
+	u.send(msg)
+	if !old.isClosed() {
+		t.Error("the old client was left open")
+	}
+	if f.isClosed() {
+		t.Error("the new client was closed")
+	}
+}
+
+func TestImages(t *testing.T) {
+	f := testDocker()
+	f.addImage("sha256:5d41402abc4b2a76b9719d911017c592", "registry.local:5000/shop/api:dev", "shop/api:dev")
+	f.addImage("sha256:7d793037a0760186574b0282f2f435e7")
+
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys(":", "images", "enter")
+	if _, ok := u.model.(routerModel).top().(listImagesModel); !ok {
+		t.Fatal(":images did not open the images")
+	}
+	u.golden()
+}
+
+func TestFilter(t *testing.T) {
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+	u.keys("/", "shp")
+	u.golden()
+
+	t.Run("structured", func(t *testing.T) {
+		u.t = t
+		u.keys(" state=exited")
+		u.golden()
+	})
+
+	t.Run("bad term", func(t *testing.T) {
+		u.t = t
+		u.keys(" label=")
+		u.golden()
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		u.t = t
+		u.keys("enter", "esc")
+		u.golden()
+		if _, ok := u.model.(routerModel).top().(listContainersModel); !ok {
+			t.Error("esc left the screen instead of clearing the filter")
+		}
+	})
+}
+
+func TestMarks(t *testing.T) {
+	f := testDocker()
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+
+	// nginx, then the whole shop stack
+	u.keys(" ", "down", " ", "enter")
+	u.golden()
+
+	t.Run("stop", func(t *testing.T) {
+		u.t = t
+		u.keys("r", "y")
+		if _, ok := u.model.(routerModel).top().(bulkSummaryModel); !ok {
+			t.Fatal("stopping the marked containers did not show a summary")
+		}
+		u.keys("j")
+		if _, ok := u.model.(routerModel).top().(bulkSummaryModel); !ok {
+			t.Fatal("a key other than close or back closed the summary")
+		}
+		u.golden()
+
+		containers, _ := f.ContainerList(t.Context(), container.ListOptions{})
+		if len(containers) > 0 {
+			t.Errorf("%d containers still running, want none", len(containers))
+		}
+	})
+
+	t.Run("invert", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", "i")
+		u.golden()
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		u.t = t
+		// nginx is running again, so it can't go
+		f.ContainerStart(t.Context(), "6f1c2b7d9e0a4c3b8a7d", container.StartOptions{})
+		u.keys("D", "y")
+		u.golden()
+
+		containers, _ := f.ContainerList(t.Context(), container.ListOptions{All: true})
+		if len(containers) != 1 {
+			t.Errorf("%d containers left, want only nginx", len(containers))
+		}
+	})
+
+	t.Run("failures stay marked", func(t *testing.T) {
+		u.t = t
+		u.keys("esc")
+		u.golden()
+	})
+
+	t.Run("mark command", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", ":", "mark state=running", "enter")
+		u.golden()
+	})
+}
+
+func TestProtected(t *testing.T) {
+	c := DefaultConfig()
+	c.Containers.Protected = []ProtectedPattern{{Name: "shop-*"}}
+	useConfig(t, c)
+
+	f := testDocker()
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("down", "enter", "down", "r")
+	u.golden()
+
+	running := func() bool {
+		containers, _ := f.ContainerList(t.Context(), container.ListOptions{})
+		return slices.ContainsFunc(containers, func(c container.Summary) bool { return c.ID == "a1b2c3d4e5f60718293a" })
+	}
+
+	t.Run("wrong name", func(t *testing.T) {
+		u.t = t
+		u.keys("y", "enter")
+		u.golden()
+		if !running() {
+			t.Error("shop-api was stopped without typing its name")
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		u.t = t
+		u.keys("backspace", "shop-api", "enter")
+		if running() {
+			t.Error("shop-api is still running")
+		}
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	SetReadOnly(true)
+	t.Cleanup(func() { SetReadOnly(false) })
+
+	f := testDocker()
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("r", "D")
+	u.golden()
+
+	containers, _ := f.ContainerList(t.Context(), container.ListOptions{All: true})
+	if len(containers) != 4 || containers[0].State != container.StateRunning {
+		t.Error("read-only mode let nginx be stopped or removed")
+	}
+
+	t.Run("palette", func(t *testing.T) {
+		u.t = t
+		u.keys(":", "stop nginx", "enter")
+		u.golden()
+	})
+}
+
+func TestResize(t *testing.T) {
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+	u.send(tea.WindowSizeMsg{Width: 80, Height: 16})
+	u.golden()
+
+	t.Run("full IDs", func(t *testing.T) {
+		u.t = t
+		u.send(tea.WindowSizeMsg{Width: 200, Height: 16})
+		u.keys("I")
+		u.golden()
+	})
+
+	t.Run("wider", func(t *testing.T) {
+		u.t = t
+		u.keys("I")
+		u.golden()
+	})
+}
+
+func TestPorts(t *testing.T) {
+	c := DefaultConfig()
+	c.Containers.Columns = []string{"indicator", "name", "state", "ports"}
+	useConfig(t, c)
+
+	opened, copied := []string{}, []string{}
+	openURL = func(url string) error { opened = append(opened, url); return nil }
+	copyText = func(text string, done func(error) tea.Msg) tea.Cmd {
+		copied = append(copied, text)
+		return func() tea.Msg { return done(nil) }
+	}
+	t.Cleanup(func() {
+		openURL = func(string) error { return nil }
+		copyText = func(string, func(error) tea.Msg) tea.Cmd { return nil }
+	})
+
+	f := testDocker()
+	f.setPorts("6f1c2b7d9e0a4c3b8a7d",
+		container.Port{IP: "0.0.0.0", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+		container.Port{IP: "::", PublicPort: 8080, PrivatePort: 80, Type: "tcp"},
+		container.Port{PrivatePort: 443, Type: "tcp"},
+	)
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("y")
+	u.golden()
+	if len(copied) != 1 || copied[0] != "http://localhost:8080" {
+		t.Errorf("copied %q, want the URL of port 8080", copied)
+	}
+
+	t.Run("open", func(t *testing.T) {
+		u.t = t
+		u.keys("w")
+		if len(opened) != 1 || opened[0] != "http://localhost:8080" {
+			t.Errorf("opened %q, want the URL of port 8080", opened)
+		}
+	})
+
+	t.Run("unpublished", func(t *testing.T) {
+		u.t = t
+		u.keys("down", "down", "w")
+		u.golden()
+		if len(opened) != 1 {
+			t.Errorf("opened %q for a container publishing nothing", opened)
+		}
+	})
+}
+
+func TestHealth(t *testing.T) {
+	probe := func(minute int, exitCode int, output string) *container.HealthcheckResult {
+		start := time.Date(2025, 1, 1, 10, minute, 0, 0, time.UTC)
+		return &container.HealthcheckResult{Start: start, End: start.Add(120 * time.Millisecond), ExitCode: exitCode, Output: output}
+	}
+	f := testDocker()
+	f.setHealth("6f1c2b7d9e0a4c3b8a7d", &container.Health{Status: container.Healthy, Log: []*container.HealthcheckResult{
+		probe(0, 0, "ok\n"),
+		probe(1, 0, "ok\n"),
+	}})
+	f.setHealth("f0e1d2c3b4a596877869", &container.Health{Status: container.Unhealthy, FailingStreak: 3, Log: []*container.HealthcheckResult{
+		probe(0, 0, "accepting connections"),
+		probe(1, 1, "no response\ncheck timed out"),
+	}})
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("down", "enter")
+	u.golden()
+
+	t.Run("probes", func(t *testing.T) {
+		u.t = t
+		u.keys("down", "down", "H")
+		u.golden()
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", "/", "health=unhealthy", "enter")
+		u.golden()
+	})
+}
+
+func TestExit(t *testing.T) {
+	f := testDocker()
+	f.addContainer("c4d5e6f708192a3b4c5d", "batch", "batch:1.4", container.StateExited, "Exited (137) 2 minutes ago", "")
+	f.setExit("c4d5e6f708192a3b4c5d", container.State{
+		ExitCode:   137,
+		OOMKilled:  true,
+		FinishedAt: "2025-01-01T10:05:00.123456789Z",
+	}, 4)
+	logs := ""
+	for i := 1; i <= 40; i++ {
+		logs += fmt.Sprintf("processed chunk %d\n", i)
+	}
+	f.setLogs("c4d5e6f708192a3b4c5d", logs)
+
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("e")
+	u.golden()
+
+	t.Run("running", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", "down", "e")
+		u.golden()
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		f.failLogs("c4d5e6f708192a3b4c5d", errors.New("configured logging driver does not support reading"))
+		u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+		u.keys("e")
+		u.golden()
+	})
+}
+
+func TestCrashLoops(t *testing.T) {
+	useCrashes(t)
+	f := testDocker()
+	now := time.Now()
+	for i, code := range []int{1, 1, 2, 1} {
+		f.addEvent(containerEvent(events.ActionDie, "0b9e8d7c6a5f4e3d2c1b", "worker", code, now.Add(-time.Duration(4-i)*time.Minute)))
+	}
+	// Stopped and restarted by hand, not crashing
+	f.addEvent(containerEvent(events.ActionKill, "6f1c2b7d9e0a4c3b8a7d", "nginx", 0, now.Add(-time.Minute)))
+	f.addEvent(containerEvent(events.ActionDie, "6f1c2b7d9e0a4c3b8a7d", "nginx", 143, now.Add(-time.Minute)))
+
+	u := newTUI(t, InitIndexModel(f))
+	u.golden()
+
+	t.Run("containers", func(t *testing.T) {
+		u.t = t
+		u.keys("enter")
+		u.golden()
+	})
+
+	t.Run("crashes", func(t *testing.T) {
+		u.t = t
+		u.keys("!")
+		u.golden()
+	})
+
+	t.Run("endpoint", func(t *testing.T) {
+		useCrashes(t)
+		// The same container ID as the local worker, on another daemon
+		remote := newFakeDocker()
+		remote.addContainer("0b9e8d7c6a5f4e3d2c1b", "worker", "busybox:latest", container.StateRestarting, "Restarting (1) 5 seconds ago", "")
+		for i := range 4 {
+			remote.addEvent(containerEvent(events.ActionDie, "0b9e8d7c6a5f4e3d2c1b", "worker", 1, now.Add(-time.Duration(4-i)*time.Minute)))
+		}
+		useRemoteHost(t, Endpoint{Name: "prod", Host: "ssh://prod.example.com"}, remote)
+
+		u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+		u.golden()
+		if _, ok := crashes.loop(LocalHostName, "0b9e8d7c6a5f4e3d2c1b", time.Now()); ok {
+			t.Error("the crashes on prod were put down to the local worker")
+		}
+
+		t.Run("crashes", func(t *testing.T) {
+			u.t = t
+			u.keys("!")
+			u.golden()
+		})
+	})
+}
+
+func TestProcesses(t *testing.T) {
+	f := testDocker()
+	f.setTop("6f1c2b7d9e0a4c3b8a7d", []string{"PID", "USER", "%CPU", "%MEM", "COMMAND"},
+		[]string{"4242", "root", "0.0", "0.4", "nginx: master process nginx -g daemon off;"},
+		[]string{"4301", "nginx", "2.5", "0.2", "nginx: worker process"},
+		[]string{"4302", "nginx", "0.7", "0.3", "nginx: worker process"},
+	)
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+	u.keys("p")
+	u.golden()
+
+	t.Run("sorted", func(t *testing.T) {
+		u.t = t
+		u.keys("o", "O")
+		u.golden()
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		u.t = t
+		u.keys("s", "K")
+		u.golden()
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		u.t = t
+		u.keys("enter")
+		u.golden()
+		want := []string{"sh", "-c", signalScript, "sh", "HUP", "nginx: master process nginx -g daemon off;", ""}
+		if ran := f.ran(); len(ran) != 1 || !slices.Equal(ran[0].cmd, want) {
+			t.Errorf("ran %+v, want %q", ran, want)
+		}
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		u.t = t
+		f.execExit = 3
+		u.keys("down", "K", "enter")
+		u.golden()
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		u.t = t
+		u.keys("esc", "down", "down", "p")
+		u.golden()
+	})
+}
+
+func TestSort(t *testing.T) {
+	path := useState(t)
+	c := DefaultConfig()
+	c.Containers.Columns = []string{"indicator", "name", "state", "cpu", "mem"}
+	useConfig(t, c)
+
+	f := testDocker()
+	f.setStats("6f1c2b7d9e0a4c3b8a7d", 12.5, 64<<20)
+	f.setStats("a1b2c3d4e5f60718293a", 40, 256<<20)
+	f.setStats("f0e1d2c3b4a596877869", 3, 512<<20)
+	u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+
+	// name → state → created → image → uptime → cpu, highest first
+	u.keys("o", "o", "o", "o", "o", "O")
+	u.golden()
+
+	SetStatePath(path)
+	if got, want := CurrentState().ContainersSort, (containerSort{Key: "cpu", Desc: true}); got != want {
+		t.Errorf("remembered sort = %v, want %v", got, want)
+	}
+
+	t.Run("next session", func(t *testing.T) {
+		u := newTUI(t, InitListContainersModel(f, testWidth, testHeight))
+		u.keys("enter")
+		u.golden()
+	})
+}
+
+func TestLogs(t *testing.T) {
+	u := newTUI(t, InitListContainersModel(testDocker(), testWidth, testHeight))
+
+	// Open the logs of a compose service, then come back to the same spot
+	u.keys("down", "enter", "down", "down", "enter")
+	u.golden()
+
+	t.Run("back", func(t *testing.T) {
+		u.t = t
+		u.keys("esc")
+		u.golden()
+
+		l := u.model.(routerModel).top().(listContainersModel)
+		if row, ok := l.selected(); !ok || row.Name != "shop-db" {
+			t.Errorf("selected row after going back = %q, want shop-db", row.Name)
+		}
+	})
+}
+
+func TestLogsModel(t *testing.T) {
+	u := newTUI(t, InitLogsModel(testDocker(), "6f1c2b7d9e0a4c3b8a7d", "nginx"))
+	u.golden()
+
+	t.Run("missing container", func(t *testing.T) {
+		u := newTUI(t, InitLogsModel(testDocker(), "gone", "gone"))
+		u.golden()
+	})
+}
@@ -0,0 +1,237 @@
+package src
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	volumeTypes "github.com/docker/docker/api/types/volume"
+)
+
+// List Volumes Model
+
+type listVolumesModel struct {
+	keys         scrollKeys
+	dockerClient Docker
+	viewID       int64
+	width        int
+	height       int
+	table        table.Model
+	loading      bool
+	status       statusBar
+}
+
+type Volume struct {
+	Name       string
+	Driver     string
+	Scope      string
+	Mountpoint string
+	CreatedAt  string
+}
+
+func init() {
+	registerCommand(command{
+		name: "volumes",
+		run: func(env commandEnv, arg string) (tea.Cmd, error) {
+			return push(InitListVolumesModel(env.dockerClient, env.width, env.height)), nil
+		},
+	})
+}
+
+func InitListVolumesModel(dockerClient Docker, width int, height int) listVolumesModel {
+	columns := []table.Column{
+		{Title: "Name", Width: 40},
+		{Title: "Driver", Width: 10},
+		{Title: "Scope", Width: 8},
+		{Title: "Created", Width: 22},
+		{Title: "Mountpoint", Width: 60},
+	}
+
+	keys := newScrollKeys("volumes")
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithKeyMap(keys.tableKeyMap()),
+		table.WithHeight(height-10),
+	)
+
+	t.SetStyles(tableStyles())
+
+	l := listVolumesModel{
+		keys:         keys,
+		dockerClient: dockerClient,
+		width:        width,
+		height:       height,
+		table:        t,
+		viewID:       nextViewID(),
+		status:       newStatusBar(),
+	}
+	l.loading = true
+	l.status.StartLoading()
+
+	return l
+}
+
+type volumesMsg struct {
+	viewID  int64
+	volumes []Volume
+	err     error
+}
+
+func (l listVolumesModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Volumes"), l.status.Tick(), l.fetchCmd(), tickCmd(l.viewID))
+}
+
+func (l listVolumesModel) client() Docker {
+	return l.dockerClient
+}
+
+func (l listVolumesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case configChangedMsg:
+		l.keys = newScrollKeys("volumes")
+		l.table.KeyMap = l.keys.tableKeyMap()
+		l.table.SetStyles(tableStyles())
+		return l, nil
+
+	case tea.WindowSizeMsg:
+		l.width = msg.Width
+		l.height = msg.Height
+		return l, nil
+
+	case tea.KeyMsg:
+		switch {
+
+		case key.Matches(msg, l.keys.Help):
+			return l, showHelp("volumes", l.keys)
+
+		case key.Matches(msg, l.keys.Back):
+			return l, pop()
+
+		case key.Matches(msg, l.keys.Quit):
+			return l, tea.Quit
+		}
+
+	case volumesMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		l.loading = false
+		l.status.StopLoading()
+		if msg.err != nil {
+			l.status.Error(msg.err)
+			return l, nil
+		}
+		l.table.SetRows(volumeRows(msg.volumes))
+		return l, nil
+
+	case pingMsg:
+		if msg.viewID != l.viewID || msg.err != nil {
+			return l, nil
+		}
+		l.status.Recovered()
+		cmd = l.refresh()
+		return l, cmd
+
+	case spinner.TickMsg:
+		l.status, cmd = l.status.Update(msg)
+		return l, cmd
+
+	case tickMsg:
+		if msg.viewID != l.viewID {
+			return l, nil
+		}
+		cmd = l.refresh()
+		return l, tea.Batch(cmd, tickCmd(l.viewID))
+	}
+
+	l.table, cmd = l.table.Update(msg)
+
+	return l, cmd
+}
+
+// refresh asks for the volumes again unless a request is already on its
+// way. While the daemon is unreachable it only pings it.
+func (l *listVolumesModel) refresh() tea.Cmd {
+	if l.status.Unreachable() {
+		return pingCmd(l.viewID, l.dockerClient)
+	}
+	if l.loading {
+		return nil
+	}
+	l.loading = true
+	return l.fetchCmd()
+}
+
+func (l listVolumesModel) fetchCmd() tea.Cmd {
+	viewID, dockerClient := l.viewID, l.dockerClient
+	return func() tea.Msg {
+		ctx, cancel := callCtx()
+		defer cancel()
+
+		volumes, err := FetchVolumes(ctx, dockerClient)
+		return volumesMsg{viewID: viewID, volumes: volumes, err: err}
+	}
+}
+
+func (l listVolumesModel) View() string {
+	doc := strings.Builder{}
+
+	title := lipgloss.PlaceHorizontal(l.width, lipgloss.Left, ContainerTitleStyle.Render("VOLUMES"))
+
+	doc.WriteString(title)
+
+	doc.WriteString("\n\n")
+
+	doc.WriteString(TableStyle.Render(l.table.View()) + "\n")
+
+	if status := l.status.View(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+
+	doc.WriteString(helpLine(l.keys))
+
+	return doc.String()
+}
+
+func volumeRows(volumes []Volume) []table.Row {
+	rows := []table.Row{}
+	for _, v := range volumes {
+		rows = append(rows, table.Row{v.Name, v.Driver, v.Scope, v.CreatedAt, v.Mountpoint})
+	}
+	return rows
+}
+
+func FetchVolumes(ctx context.Context, dockerClient Docker) ([]Volume, error) {
+	var response volumeTypes.ListResponse
+	err := retry(ctx, func() (err error) {
+		response, err = dockerClient.VolumeList(ctx, volumeTypes.ListOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make([]Volume, 0, len(response.Volumes))
+	for _, v := range response.Volumes {
+		volumes = append(volumes, Volume{
+			Name:       v.Name,
+			Driver:     v.Driver,
+			Scope:      v.Scope,
+			Mountpoint: v.Mountpoint,
+			CreatedAt:  v.CreatedAt,
+		})
+	}
+
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+
+	return volumes, nil
+}